@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"gs-write/pkg/config"
+	"gs-write/pkg/sheets"
 	"strconv"
 	"strings"
 
@@ -16,15 +17,29 @@ var configCmd = &cobra.Command{
 gs-writeの設定を管理します。
 
 Available settings / 利用可能な設定:
-  freeze.rows       - Number of rows to freeze / 固定する行数 (default: not set)
-  freeze.cols       - Number of columns to freeze / 固定する列数 (default: not set)
-  filter.header_row - Header row for basic filter / フィルタのヘッダー行 (default: not set)
+  freeze.rows             - Number of rows to freeze / 固定する行数 (default: not set)
+  freeze.cols             - Number of columns to freeze / 固定する列数 (default: not set)
+  filter.header_row       - Header row for basic filter / フィルタのヘッダー行 (default: not set)
+  input.delimiter         - Field delimiter of input / 入力の区切り文字 (default: not set)
+  input.encoding          - Character encoding of input / 入力の文字エンコーディング (default: not set)
+  format.auto_resize      - Fit column widths to content / 列幅を内容に合わせる (default: false)
+  format.max_col_width    - Maximum auto-resized column width in pixels / 自動調整した列幅の上限 (default: 0 = unlimited)
+  style.header.preset     - Style preset of the header row / ヘッダー行のスタイル (default: none)
+  style.header.bold       - Bold header text / ヘッダーを太字にする (default: by preset)
+  style.header.background - Header background color / ヘッダーの背景色 (default: by preset)
+  style.header.foreground - Header text color / ヘッダーの文字色 (default: by preset)
+  style.header.border     - Header borders / ヘッダーの罫線 (default: by preset)
+  style.header.align      - Header alignment / ヘッダーの配置 (default: by preset)
 
 Examples / 使用例:
   gs-write config list
   gs-write config get freeze.rows
   gs-write config set freeze.rows 1
   gs-write config set filter.header_row 1
+  gs-write config set input.delimiter tab
+  gs-write config set input.encoding sjis
+  gs-write config set format.auto_resize true
+  gs-write config set style.header.preset dark
   gs-write config unset freeze.rows`,
 }
 
@@ -43,9 +58,19 @@ var configGetCmd = &cobra.Command{
 指定した設定の値を取得します。
 
 Available keys / 利用可能なキー:
-  freeze.rows       - Number of rows to freeze / 固定する行数
-  freeze.cols       - Number of columns to freeze / 固定する列数
-  filter.header_row - Header row for basic filter / フィルタのヘッダー行`,
+  freeze.rows             - Number of rows to freeze / 固定する行数
+  freeze.cols             - Number of columns to freeze / 固定する列数
+  filter.header_row       - Header row for basic filter / フィルタのヘッダー行
+  input.delimiter         - Field delimiter of input / 入力の区切り文字
+  input.encoding          - Character encoding of input / 入力の文字エンコーディング
+  format.auto_resize      - Fit column widths to content / 列幅を内容に合わせる
+  format.max_col_width    - Maximum auto-resized column width in pixels / 自動調整した列幅の上限
+  style.header.preset     - Style preset of the header row / ヘッダー行のスタイル
+  style.header.bold       - Bold header text / ヘッダーを太字にする
+  style.header.background - Header background color / ヘッダーの背景色
+  style.header.foreground - Header text color / ヘッダーの文字色
+  style.header.border     - Header borders / ヘッダーの罫線
+  style.header.align      - Header alignment / ヘッダーの配置`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConfigGet,
 }
@@ -57,14 +82,30 @@ var configSetCmd = &cobra.Command{
 指定した設定の値を変更します。
 
 Available keys / 利用可能なキー:
-  freeze.rows       - Number of rows to freeze / 固定する行数 (must be non-negative integer / 非負の整数)
-  freeze.cols       - Number of columns to freeze / 固定する列数 (must be non-negative integer / 非負の整数)
-  filter.header_row - Header row for basic filter / フィルタのヘッダー行 (must be non-negative integer / 非負の整数)
+  freeze.rows             - Number of rows to freeze / 固定する行数 (must be non-negative integer / 非負の整数)
+  freeze.cols             - Number of columns to freeze / 固定する列数 (must be non-negative integer / 非負の整数)
+  filter.header_row       - Header row for basic filter / フィルタのヘッダー行 (must be non-negative integer / 非負の整数)
+  input.delimiter         - Field delimiter of input / 入力の区切り文字 (single character or "tab" / 1文字または"tab")
+  input.encoding          - Character encoding of input / 入力の文字エンコーディング (see "gs-write encodings" / "gs-write encodings"を参照)
+  format.auto_resize      - Fit column widths to content / 列幅を内容に合わせる (true or false / trueまたはfalse)
+  format.max_col_width    - Maximum auto-resized column width in pixels / 自動調整した列幅の上限 (must be non-negative integer / 非負の整数)
+  style.header.preset     - Style preset of the header row / ヘッダー行のスタイル (none, bold, bold+background, dark)
+  style.header.bold       - Bold header text / ヘッダーを太字にする (true or false / trueまたはfalse)
+  style.header.background - Header background color / ヘッダーの背景色 (#RRGGBB)
+  style.header.foreground - Header text color / ヘッダーの文字色 (#RRGGBB)
+  style.header.border     - Header borders / ヘッダーの罫線 (none, bottom, all)
+  style.header.align      - Header alignment / ヘッダーの配置 (left, center, right)
 
 Examples / 使用例:
   gs-write config set freeze.rows 1
   gs-write config set freeze.cols 2
-  gs-write config set filter.header_row 1`,
+  gs-write config set filter.header_row 1
+  gs-write config set input.delimiter ";"
+  gs-write config set input.encoding cp932
+  gs-write config set format.auto_resize true
+  gs-write config set format.max_col_width 400
+  gs-write config set style.header.preset bold+background
+  gs-write config set style.header.background "#FFF2CC"`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -76,14 +117,28 @@ var configUnsetCmd = &cobra.Command{
 設定を削除し、デフォルト動作に戻します。
 
 Available keys / 利用可能なキー:
-  freeze.rows       - Number of rows to freeze / 固定する行数
-  freeze.cols       - Number of columns to freeze / 固定する列数
-  filter.header_row - Header row for basic filter / フィルタのヘッダー行
+  freeze.rows             - Number of rows to freeze / 固定する行数
+  freeze.cols             - Number of columns to freeze / 固定する列数
+  filter.header_row       - Header row for basic filter / フィルタのヘッダー行
+  input.delimiter         - Field delimiter of input / 入力の区切り文字
+  input.encoding          - Character encoding of input / 入力の文字エンコーディング
+  format.auto_resize      - Fit column widths to content / 列幅を内容に合わせる
+  format.max_col_width    - Maximum auto-resized column width in pixels / 自動調整した列幅の上限
+  style.header.preset     - Style preset of the header row / ヘッダー行のスタイル
+  style.header.bold       - Bold header text / ヘッダーを太字にする
+  style.header.background - Header background color / ヘッダーの背景色
+  style.header.foreground - Header text color / ヘッダーの文字色
+  style.header.border     - Header borders / ヘッダーの罫線
+  style.header.align      - Header alignment / ヘッダーの配置
 
 Examples / 使用例:
   gs-write config unset freeze.rows
   gs-write config unset freeze.cols
-  gs-write config unset filter.header_row`,
+  gs-write config unset filter.header_row
+  gs-write config unset input.delimiter
+  gs-write config unset input.encoding
+  gs-write config unset format.auto_resize
+  gs-write config unset style.header.preset`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConfigUnset,
 }
@@ -113,6 +168,32 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 	headerRow, _ := cfg.GetFilterHeaderRow()
 	fmt.Printf("  filter.header_row = %d\n", headerRow)
 
+	if delimiter, ok := cfg.GetInputDelimiter(); ok {
+		fmt.Printf("  input.delimiter = %q\n", delimiter)
+	} else {
+		fmt.Println("  input.delimiter = auto")
+	}
+
+	if encodingName, ok := cfg.GetInputEncoding(); ok {
+		fmt.Printf("  input.encoding = %s\n", encodingName)
+	} else {
+		fmt.Println("  input.encoding = auto")
+	}
+
+	autoResize, _ := cfg.GetFormatAutoResize()
+	fmt.Printf("  format.auto_resize = %t\n", autoResize)
+
+	maxColWidth, _ := cfg.GetFormatMaxColWidth()
+	fmt.Printf("  format.max_col_width = %d\n", maxColWidth)
+
+	headerStyle, err := configHeaderStyle(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid style.header in config: %w", err)
+	}
+	for _, key := range headerStyleKeys {
+		fmt.Printf("  %s = %s\n", key, headerStyleValue(cfg, headerStyle, key))
+	}
+
 	return nil
 }
 
@@ -137,6 +218,36 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		// Always return the effective value (user configured or default)
 		headerRow, _ := cfg.GetFilterHeaderRow()
 		fmt.Println(headerRow)
+	case "input.delimiter":
+		// Always return the effective value (user configured or default)
+		if delimiter, ok := cfg.GetInputDelimiter(); ok {
+			fmt.Printf("%q\n", delimiter)
+		} else {
+			fmt.Println("auto")
+		}
+	case "input.encoding":
+		// Always return the effective value (user configured or default)
+		if encodingName, ok := cfg.GetInputEncoding(); ok {
+			fmt.Println(encodingName)
+		} else {
+			fmt.Println("auto")
+		}
+	case "format.auto_resize":
+		// Always return the effective value (user configured or default)
+		autoResize, _ := cfg.GetFormatAutoResize()
+		fmt.Println(autoResize)
+	case "format.max_col_width":
+		// Always return the effective value (user configured or default)
+		maxColWidth, _ := cfg.GetFormatMaxColWidth()
+		fmt.Println(maxColWidth)
+	case "style.header.preset", "style.header.bold", "style.header.background",
+		"style.header.foreground", "style.header.border", "style.header.align":
+		// Always return the effective value (user configured or by the preset)
+		headerStyle, err := configHeaderStyle(cfg)
+		if err != nil {
+			return fmt.Errorf("invalid style.header in config: %w", err)
+		}
+		fmt.Println(headerStyleValue(cfg, headerStyle, key))
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -187,6 +298,83 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		cfg.SetFilterHeaderRow(value)
 		fmt.Printf("Set filter.header_row = %d\n", value)
 
+	case "input.delimiter":
+		delimiter, err := parseDelimiter(valueStr)
+		if err != nil {
+			return fmt.Errorf("invalid value for input.delimiter: %w", err)
+		}
+		cfg.SetInputDelimiter(string(delimiter))
+		fmt.Printf("Set input.delimiter = %q\n", string(delimiter))
+
+	case "input.encoding":
+		if err := validateEncoding(valueStr); err != nil {
+			return fmt.Errorf("invalid value for input.encoding: %w", err)
+		}
+		cfg.SetInputEncoding(valueStr)
+		fmt.Printf("Set input.encoding = %s\n", valueStr)
+
+	case "format.auto_resize":
+		value, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			return fmt.Errorf("invalid value for format.auto_resize: must be true or false")
+		}
+		cfg.SetFormatAutoResize(value)
+		fmt.Printf("Set format.auto_resize = %t\n", value)
+
+	case "format.max_col_width":
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return fmt.Errorf("invalid value for format.max_col_width: must be an integer")
+		}
+		if value < 0 {
+			return fmt.Errorf("invalid value for format.max_col_width: must be non-negative (got: %d)", value)
+		}
+		cfg.SetFormatMaxColWidth(value)
+		fmt.Printf("Set format.max_col_width = %d\n", value)
+
+	case "style.header.preset":
+		if _, err := sheets.HeaderStylePreset(valueStr); err != nil {
+			return fmt.Errorf("invalid value for style.header.preset: %w", err)
+		}
+		cfg.SetHeaderPreset(valueStr)
+		fmt.Printf("Set style.header.preset = %s\n", valueStr)
+
+	case "style.header.bold":
+		value, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			return fmt.Errorf("invalid value for style.header.bold: must be true or false")
+		}
+		cfg.SetHeaderBold(value)
+		fmt.Printf("Set style.header.bold = %t\n", value)
+
+	case "style.header.background":
+		if err := (sheets.HeaderStyle{Background: valueStr}).Validate(); err != nil {
+			return fmt.Errorf("invalid value for style.header.background: %w", err)
+		}
+		cfg.SetHeaderBackground(valueStr)
+		fmt.Printf("Set style.header.background = %s\n", valueStr)
+
+	case "style.header.foreground":
+		if err := (sheets.HeaderStyle{Foreground: valueStr}).Validate(); err != nil {
+			return fmt.Errorf("invalid value for style.header.foreground: %w", err)
+		}
+		cfg.SetHeaderForeground(valueStr)
+		fmt.Printf("Set style.header.foreground = %s\n", valueStr)
+
+	case "style.header.border":
+		if err := (sheets.HeaderStyle{Border: valueStr}).Validate(); err != nil {
+			return fmt.Errorf("invalid value for style.header.border: %w", err)
+		}
+		cfg.SetHeaderBorder(valueStr)
+		fmt.Printf("Set style.header.border = %s\n", valueStr)
+
+	case "style.header.align":
+		if err := (sheets.HeaderStyle{Align: valueStr}).Validate(); err != nil {
+			return fmt.Errorf("invalid value for style.header.align: %w", err)
+		}
+		cfg.SetHeaderAlign(valueStr)
+		fmt.Printf("Set style.header.align = %s\n", valueStr)
+
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -222,6 +410,46 @@ func runConfigUnset(cmd *cobra.Command, args []string) error {
 		cfg.UnsetFilterHeaderRow()
 		fmt.Println("Unset filter.header_row")
 
+	case "input.delimiter":
+		cfg.UnsetInputDelimiter()
+		fmt.Println("Unset input.delimiter")
+
+	case "input.encoding":
+		cfg.UnsetInputEncoding()
+		fmt.Println("Unset input.encoding")
+
+	case "format.auto_resize":
+		cfg.UnsetFormatAutoResize()
+		fmt.Println("Unset format.auto_resize")
+
+	case "format.max_col_width":
+		cfg.UnsetFormatMaxColWidth()
+		fmt.Println("Unset format.max_col_width")
+
+	case "style.header.preset":
+		cfg.UnsetHeaderPreset()
+		fmt.Println("Unset style.header.preset")
+
+	case "style.header.bold":
+		cfg.UnsetHeaderBold()
+		fmt.Println("Unset style.header.bold")
+
+	case "style.header.background":
+		cfg.UnsetHeaderBackground()
+		fmt.Println("Unset style.header.background")
+
+	case "style.header.foreground":
+		cfg.UnsetHeaderForeground()
+		fmt.Println("Unset style.header.foreground")
+
+	case "style.header.border":
+		cfg.UnsetHeaderBorder()
+		fmt.Println("Unset style.header.border")
+
+	case "style.header.align":
+		cfg.UnsetHeaderAlign()
+		fmt.Println("Unset style.header.align")
+
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -236,6 +464,43 @@ func runConfigUnset(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// headerStyleKeys are the configuration keys of the header style
+var headerStyleKeys = []string{
+	"style.header.preset",
+	"style.header.bold",
+	"style.header.background",
+	"style.header.foreground",
+	"style.header.border",
+	"style.header.align",
+}
+
+// headerStyleValue returns the effective value of a header style key, where style is
+// the header style of the config (nil means no style)
+func headerStyleValue(cfg *config.UserConfig, style *sheets.HeaderStyle, key string) string {
+	if style == nil {
+		style = &sheets.HeaderStyle{}
+	}
+	value := ""
+	switch key {
+	case "style.header.preset":
+		value, _ = cfg.GetHeaderPreset()
+	case "style.header.bold":
+		value = strconv.FormatBool(style.Bold)
+	case "style.header.background":
+		value = style.Background
+	case "style.header.foreground":
+		value = style.Foreground
+	case "style.header.border":
+		value = style.Border
+	case "style.header.align":
+		value = style.Align
+	}
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
 // normalizeKey converts various key formats to dot notation
 func normalizeKey(key string) string {
 	// Replace underscores and hyphens with dots
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// encodingSpec is a character encoding supported by --encoding
+type encodingSpec struct {
+	// name is the canonical name
+	name string
+	// aliases are other accepted names
+	aliases []string
+	// description is shown by the encodings command
+	description string
+	// encoding converts the input to UTF-8 (nil means no conversion)
+	encoding encoding.Encoding
+}
+
+// supportedEncodings lists the encodings accepted by --encoding. Names are matched
+// case-insensitively.
+var supportedEncodings = []encodingSpec{
+	{name: "utf-8", aliases: []string{"utf8"}, description: "UTF-8 (BOM is removed)"},
+	{name: "utf-16", aliases: []string{"utf16", "unicode"}, description: "UTF-16 with BOM (little endian without BOM)", encoding: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)},
+	{name: "utf-16le", aliases: []string{"utf16le"}, description: "UTF-16 little endian, e.g. Excel \"Unicode Text\"", encoding: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)},
+	{name: "utf-16be", aliases: []string{"utf16be"}, description: "UTF-16 big endian", encoding: unicode.UTF16(unicode.BigEndian, unicode.UseBOM)},
+	{name: "sjis", aliases: []string{"shift-jis", "shift_jis"}, description: "Shift_JIS (Japanese)", encoding: japanese.ShiftJIS},
+	{name: "cp932", aliases: []string{"windows-31j", "ms932"}, description: "Windows Shift_JIS with NEC and IBM extensions (Japanese)", encoding: japanese.ShiftJIS},
+	{name: "euc-jp", aliases: []string{"euc_jp", "eucjp"}, description: "EUC-JP (Japanese)", encoding: japanese.EUCJP},
+	{name: "iso-2022-jp", aliases: []string{"iso2022jp", "jis", "csiso2022jp"}, description: "ISO-2022-JP / JIS (Japanese e-mail)", encoding: japanese.ISO2022JP},
+	{name: "windows-1252", aliases: []string{"cp1252"}, description: "Windows Western European", encoding: charmap.Windows1252},
+	{name: "iso-8859-1", aliases: []string{"latin1", "latin-1", "iso8859-1"}, description: "ISO Latin-1 (Western European)", encoding: charmap.ISO8859_1},
+	{name: "gbk", aliases: []string{"cp936"}, description: "GBK (Simplified Chinese)", encoding: simplifiedchinese.GBK},
+	{name: "gb18030", description: "GB18030 (Simplified Chinese)", encoding: simplifiedchinese.GB18030},
+	{name: "euc-kr", aliases: []string{"euckr", "cp949", "uhc"}, description: "EUC-KR / Unified Hangul Code (Korean)", encoding: korean.EUCKR},
+}
+
+// encodingsCmd represents the encodings command
+var encodingsCmd = &cobra.Command{
+	Use:   "encodings",
+	Short: "List supported character encodings / 対応している文字エンコーディングを表示",
+	Long: `List the character encodings accepted by --encoding and input.encoding, with their aliases.
+--encodingとinput.encodingで指定できる文字エンコーディングと別名を表示します。
+
+Names are case-insensitive. "auto" (the default) detects UTF-8, UTF-16 (with BOM),
+Shift_JIS, EUC-JP and ISO-2022-JP from the input.
+名前の大文字・小文字は区別しません。"auto"(デフォルト)は入力からUTF-8、UTF-16(BOM付き)、
+Shift_JIS、EUC-JP、ISO-2022-JPを自動検出します。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tALIASES\tDESCRIPTION")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", encodingAuto, "-", "Detected from the input (default)")
+		for _, spec := range supportedEncodings {
+			aliases := "-"
+			if len(spec.aliases) > 0 {
+				aliases = strings.Join(spec.aliases, ", ")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", spec.name, aliases, spec.description)
+		}
+		w.Flush()
+	},
+}
+
+// lookupEncoding returns the supported encoding with the given name or alias
+func lookupEncoding(encodingName string) (encodingSpec, bool) {
+	for _, spec := range supportedEncodings {
+		if strings.EqualFold(spec.name, encodingName) {
+			return spec, true
+		}
+		for _, alias := range spec.aliases {
+			if strings.EqualFold(alias, encodingName) {
+				return spec, true
+			}
+		}
+	}
+	return encodingSpec{}, false
+}
+
+// validateEncoding checks that the encoding name is "auto" or a supported encoding
+func validateEncoding(encodingName string) error {
+	if strings.EqualFold(encodingName, encodingAuto) {
+		return nil
+	}
+	_, err := getEncodingDecoder(encodingName)
+	return err
+}
+
+// getEncodingDecoder returns the decoder for the specified encoding name
+// (nil for UTF-8, which needs no conversion)
+func getEncodingDecoder(encodingName string) (*encoding.Decoder, error) {
+	spec, ok := lookupEncoding(encodingName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding: %s (run \"gs-write encodings\" to list supported encodings)", encodingName)
+	}
+	if spec.encoding == nil {
+		// No conversion needed for UTF-8
+		return nil, nil
+	}
+	return spec.encoding.NewDecoder(), nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupEncoding(t *testing.T) {
+	seen := map[string]string{}
+	for _, spec := range supportedEncodings {
+		for _, name := range append([]string{spec.name}, spec.aliases...) {
+			if other, ok := seen[strings.ToLower(name)]; ok {
+				t.Errorf("%q is a name of both %q and %q", name, other, spec.name)
+			}
+			seen[strings.ToLower(name)] = spec.name
+
+			for _, given := range []string{name, strings.ToUpper(name)} {
+				t.Run(given, func(t *testing.T) {
+					got, ok := lookupEncoding(given)
+					if !ok {
+						t.Fatalf("lookupEncoding(%q) found nothing, want %q", given, spec.name)
+					}
+					if got.name != spec.name {
+						t.Errorf("lookupEncoding(%q) = %q, want %q", given, got.name, spec.name)
+					}
+					if err := validateEncoding(given); err != nil {
+						t.Errorf("validateEncoding(%q) error = %v", given, err)
+					}
+				})
+			}
+		}
+	}
+
+	for _, name := range []string{"", "utf-32", "shiftjis2", "auto"} {
+		t.Run("unknown "+name, func(t *testing.T) {
+			if _, ok := lookupEncoding(name); ok {
+				t.Errorf("lookupEncoding(%q) found an encoding, want none", name)
+			}
+		})
+	}
+	if err := validateEncoding("utf-32"); err == nil {
+		t.Error("validateEncoding(\"utf-32\") error = nil, want an error")
+	}
+	if err := validateEncoding("AUTO"); err != nil {
+		t.Errorf("validateEncoding(\"AUTO\") error = %v", err)
+	}
+}
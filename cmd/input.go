@@ -0,0 +1,940 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gs-write/pkg/input"
+	"gs-write/pkg/sheets"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// inputTable is a table read from an input source
+type inputTable struct {
+	// name is the sheet name derived from the source (empty for stdin)
+	name string
+	// data is the parsed rows
+	data [][]string
+	// values holds typed cell values for formats that have them (nil otherwise)
+	values [][]interface{}
+	// formats holds the number formats of columns
+	formats []sheets.ColumnFormat
+}
+
+// sheet returns the sheet with the given name to write the table to
+func (t inputTable) sheet(name string, opts sheets.SheetOptions) sheets.Sheet {
+	return sheets.Sheet{Name: name, Data: t.data, Values: t.values, Formats: t.formats, Options: opts}
+}
+
+// Input formats supported by --input-format
+const (
+	formatCSV      = "csv"
+	formatColumns  = "columns"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatXLSX     = "xlsx"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatFixed    = "fixed"
+)
+
+// formatsByExtension maps file extensions to the input format used when --input-format is not set
+var formatsByExtension = map[string]string{
+	".json":     formatJSON,
+	".ndjson":   formatNDJSON,
+	".jsonl":    formatNDJSON,
+	".xlsx":     formatXLSX,
+	".md":       formatMarkdown,
+	".markdown": formatMarkdown,
+	".html":     formatHTML,
+	".htm":      formatHTML,
+}
+
+// Splitting modes of the columns input format
+const (
+	columnsModeWhitespace = "whitespace"
+	columnsModeHeader     = "header"
+)
+
+// inputOptions holds the settings used to parse input data
+type inputOptions struct {
+	// format is the input format (empty means decided by the file extension)
+	format string
+	// encoding is the character encoding of the input
+	encoding string
+	// delimiter is the field delimiter (0 means detected from the input)
+	delimiter rune
+	// columnsMode is how the columns format splits lines (whitespace or header)
+	columnsMode string
+	// maxFields is the maximum number of fields per line of the columns format (0 means unlimited)
+	maxFields int
+	// jsonArrays is how nested JSON arrays are written (serialize or explode)
+	jsonArrays string
+	// worksheets is the names of the xlsx worksheets to read (empty means all)
+	worksheets []string
+	// fixedColumns is the column layout of the fixed format
+	fixedColumns []input.FixedColumn
+	// widthUnit is the unit of the fixed format widths (bytes or chars)
+	widthUnit string
+	// lenient repairs malformed CSV rows instead of failing
+	lenient bool
+	// skipMalformed skips malformed CSV rows in lenient mode instead of repairing them
+	skipMalformed bool
+}
+
+// readInputs reads the files given as arguments, or stdin if there are none.
+// Each file becomes a table named after its base name without extension; "-" means stdin.
+// Each worksheet of an xlsx workbook becomes a table named after the worksheet, prefixed
+// with the file name when there are several files.
+func readInputs(paths []string, opts inputOptions) ([]inputTable, error) {
+	if len(paths) == 0 {
+		return readInput("-", opts)
+	}
+
+	var inputs []inputTable
+	for _, path := range paths {
+		tables, err := readInput(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			switch {
+			case table.name == "":
+				table.name = tableName(path)
+			case len(paths) > 1:
+				table.name = tableName(path) + " - " + table.name
+			}
+			inputs = append(inputs, table)
+		}
+	}
+	return inputs, nil
+}
+
+// readInput reads the tables in the file at path ("-" means stdin) in the input format.
+// Only xlsx workbooks contain named tables (worksheets); other formats yield a single
+// unnamed table.
+func readInput(path string, opts inputOptions) ([]inputTable, error) {
+	r, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	buffered := bufio.NewReaderSize(r, input.EncodingSniffSize)
+	format := detectFormat(path, buffered, opts)
+	if format != formatXLSX {
+		opts.encoding, err = resolveEncoding(path, buffered, opts.encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var tables []inputTable
+	switch format {
+	case formatXLSX:
+		tables, err = readXLSX(buffered, opts)
+	case formatMarkdown, formatHTML:
+		tables, err = readDocument(buffered, format, opts)
+	default:
+		var data [][]string
+		switch format {
+		case formatColumns:
+			data, err = readColumns(buffered, opts)
+		case formatFixed:
+			data, err = readFixed(buffered, opts)
+		case formatJSON, formatNDJSON:
+			data, err = readJSON(buffered, format, opts)
+		default:
+			data, err = readCSV(buffered, path, opts)
+		}
+		if len(data) > 0 {
+			tables = []inputTable{{data: data}}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from %s: %w", format, sourceName(path), err)
+	}
+	if len(tables) == 0 {
+		if path == "-" {
+			return nil, fmt.Errorf("no data provided")
+		}
+		return nil, fmt.Errorf("no data provided in %s", path)
+	}
+	return tables, nil
+}
+
+// formatFor returns the input format for the input at path. Without an explicit
+// format, it is decided by the file extension and defaults to CSV.
+func formatFor(path string, opts inputOptions) string {
+	if opts.format != "" {
+		return opts.format
+	}
+	if format, ok := formatsByExtension[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return formatCSV
+}
+
+// detectFormat returns the input format for the input at path, recognizing xlsx
+// workbooks by their signature when the format is not given by --input-format or
+// the file extension (e.g. on stdin)
+func detectFormat(path string, r *bufio.Reader, opts inputOptions) string {
+	format := formatFor(path, opts)
+	if opts.format == "" && format == formatCSV {
+		if magic, _ := r.Peek(4); input.IsXLSX(magic) {
+			return formatXLSX
+		}
+	}
+	return format
+}
+
+// openInput opens the file at path, or stdin for "-"
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input file: %w", err)
+	}
+	return file, nil
+}
+
+// sourceName returns the name of the input used in messages
+func sourceName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// source returns the name of the input of the table used in messages
+func (t inputTable) source() string {
+	if t.name == "" {
+		return sourceName("-")
+	}
+	return t.name
+}
+
+// encodingAuto is the --encoding value that detects the encoding from the input
+const encodingAuto = "auto"
+
+// resolveEncoding returns the encoding of the input in r. With "auto", it is detected
+// from the beginning of the input and reported on stderr. A UTF-8 byte order mark is
+// skipped so that it does not end up in the first cell.
+func resolveEncoding(path string, r *bufio.Reader, encodingName string) (string, error) {
+	// Peek returns whatever is available when the input is shorter than the sniff size
+	sample, _ := r.Peek(input.EncodingSniffSize)
+
+	if strings.EqualFold(encodingName, encodingAuto) {
+		encodingName = input.DetectEncoding(sample, len(sample) >= input.EncodingSniffSize)
+		note := ""
+		if input.UTF8BOMLength(sample) > 0 {
+			note = " (with BOM)"
+		}
+		fmt.Fprintf(os.Stderr, "Detected encoding of %s: %s%s\n", sourceName(path), encodingName, note)
+	}
+
+	decoder, err := getEncodingDecoder(encodingName)
+	if err != nil {
+		return "", err
+	}
+	if decoder == nil {
+		if n := input.UTF8BOMLength(sample); n > 0 {
+			if _, err := r.Discard(n); err != nil {
+				return "", err
+			}
+		}
+	}
+	return encodingName, nil
+}
+
+// decodeReader wraps r to convert from the specified encoding to UTF-8
+func decodeReader(r io.Reader, encodingName string) (io.Reader, error) {
+	// Get the decoder for the specified encoding
+	decoder, err := getEncodingDecoder(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder == nil {
+		// No conversion needed for UTF-8
+		return r, nil
+	}
+	// Convert from the specified encoding to UTF-8
+	return transform.NewReader(r, decoder), nil
+}
+
+// sniffDialect peeks at the beginning of r to detect the dialect and returns
+// a reader that still yields the whole input
+func sniffDialect(r io.Reader) (input.Dialect, io.Reader) {
+	buffered := bufio.NewReaderSize(r, input.SniffSize)
+	// Peek returns whatever is available when the input is shorter than SniffSize
+	sample, _ := buffered.Peek(input.SniffSize)
+	return input.SniffDialect(sample), buffered
+}
+
+// showDialects prints the detected dialect of each input without uploading
+func showDialects(paths []string, opts inputOptions) error {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	for _, path := range paths {
+		r, err := openInput(path)
+		if err != nil {
+			return err
+		}
+		buffered := bufio.NewReaderSize(r, input.EncodingSniffSize)
+		if format := detectFormat(path, buffered, opts); format != formatCSV {
+			r.Close()
+			fmt.Printf("%s: format=%s\n", sourceName(path), format)
+			continue
+		}
+
+		encodingName, err := resolveEncoding(path, buffered, opts.encoding)
+		if err != nil {
+			r.Close()
+			return err
+		}
+		reader, err := decodeReader(buffered, encodingName)
+		if err != nil {
+			r.Close()
+			return err
+		}
+		dialect, _ := sniffDialect(reader)
+		r.Close()
+		fmt.Printf("%s: %s\n", sourceName(path), dialect)
+	}
+	return nil
+}
+
+// parseDelimiter parses a delimiter given as a single character, an escape
+// sequence such as "\t", or one of the names tab, comma, semicolon, pipe and space
+func parseDelimiter(value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case "tab", `\t`:
+		return '\t', nil
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	case "pipe":
+		return '|', nil
+	case "space":
+		return ' ', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character or one of tab, comma, semicolon, pipe, space (got: %q)", value)
+	}
+	switch r := runes[0]; r {
+	case '"', '\r', '\n', utf8.RuneError:
+		return 0, fmt.Errorf("delimiter cannot be %q", r)
+	default:
+		return r, nil
+	}
+}
+
+// tableName returns the sheet name for an input file: its base name without extension
+func tableName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// readCSV reads CSV data from r with character encoding conversion. Without an
+// explicit delimiter, .tsv files are read as tab-separated values and the dialect of
+// other inputs is detected from their first bytes and reported on stderr.
+func readCSV(r io.Reader, path string, opts inputOptions) ([][]string, error) {
+	// Create a reader with encoding conversion
+	reader, err := decodeReader(r, opts.encoding)
+	if err != nil {
+		return nil, err
+	}
+	reader, delimiter := resolveDialect(reader, path, opts.delimiter)
+
+	// Parse CSV, repairing malformed rows in lenient mode
+	if opts.lenient {
+		records, report, err := input.ReadCSVLenient(reader, delimiter, opts.skipMalformed)
+		if err != nil {
+			return nil, err
+		}
+		printLenientReport(path, report)
+		return records, nil
+	}
+
+	// Otherwise stray quotes fail with their line number, even if the dialect detection
+	// found them, so that nothing is silently misread
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = delimiter
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%w (use --lenient to repair or skip malformed rows)", err)
+		}
+		return nil, err
+	}
+	return records, nil
+}
+
+// resolveDialect returns the delimiter of the CSV input in r. Without an explicit
+// delimiter, .tsv files are tab-separated and the dialect of other inputs is detected
+// from their first bytes and reported on stderr. The returned reader still yields the
+// whole input.
+func resolveDialect(r io.Reader, path string, delimiter rune) (io.Reader, rune) {
+	if delimiter == 0 && strings.EqualFold(filepath.Ext(path), ".tsv") {
+		delimiter = '\t'
+	}
+	if delimiter != 0 {
+		return r, delimiter
+	}
+
+	dialect, reader := sniffDialect(r)
+	fmt.Fprintf(os.Stderr, "Detected dialect of %s: %s\n", sourceName(path), dialect)
+	return reader, dialect.Delimiter
+}
+
+// openCSVStream opens the CSV input at path ("-" means stdin) for reading row by row
+// with character encoding conversion, without loading the whole input into memory.
+// The returned closer closes the input.
+func openCSVStream(path string, opts inputOptions) (*csv.Reader, io.Closer, error) {
+	r, err := openInput(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buffered := bufio.NewReaderSize(r, input.EncodingSniffSize)
+	if format := detectFormat(path, buffered, opts); format != formatCSV {
+		r.Close()
+		return nil, nil, fmt.Errorf("--stream only supports csv input (%s is %s)", sourceName(path), format)
+	}
+	encodingName, err := resolveEncoding(path, buffered, opts.encoding)
+	if err != nil {
+		r.Close()
+		return nil, nil, err
+	}
+	reader, err := decodeReader(buffered, encodingName)
+	if err != nil {
+		r.Close()
+		return nil, nil, err
+	}
+	reader, delimiter := resolveDialect(reader, path, opts.delimiter)
+
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = delimiter
+	return csvReader, r, nil
+}
+
+// printProgress prints the number of rows and bytes uploaded so far on stderr
+func printProgress(rows int, bytes int64) {
+	fmt.Fprintf(os.Stderr, "Uploaded %d rows (%.1f MB)\n", rows, float64(bytes)/(1<<20))
+}
+
+// maxReportedLines is the number of line numbers listed per kind of repair
+const maxReportedLines = 20
+
+// printLenientReport prints the lines repaired or skipped by lenient parsing on stderr
+func printLenientReport(path string, report input.LenientReport) {
+	if report.Empty() {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Lenient parsing of %s:\n", sourceName(path))
+	printLines := func(label string, lines []int) {
+		if len(lines) == 0 {
+			return
+		}
+		var b strings.Builder
+		for i, line := range lines {
+			if i == maxReportedLines {
+				fmt.Fprintf(&b, " ... (%d more)", len(lines)-maxReportedLines)
+				break
+			}
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, " %d", line)
+		}
+		fmt.Fprintf(os.Stderr, "  %s (%d):%s\n", label, len(lines), b.String())
+	}
+	printLines("stray quotes read as-is on lines", report.StrayQuotes)
+	printLines("short rows padded on lines", report.Padded)
+	printLines("long rows kept on lines", report.Long)
+	printLines("malformed rows skipped on lines", report.Skipped)
+}
+
+// readColumns reads whitespace-aligned command output from r with character encoding conversion
+func readColumns(r io.Reader, opts inputOptions) ([][]string, error) {
+	reader, err := decodeReader(r, opts.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.columnsMode == columnsModeHeader {
+		return input.ReadAlignedColumns(reader, opts.maxFields)
+	}
+	return input.ReadColumns(reader, opts.maxFields)
+}
+
+// readFixed reads fixed-width data from r. Byte widths are measured before the
+// character encoding conversion, character widths after it.
+func readFixed(r io.Reader, opts inputOptions) ([][]string, error) {
+	if opts.widthUnit == input.WidthChars {
+		reader, err := decodeReader(r, opts.encoding)
+		if err != nil {
+			return nil, err
+		}
+		return input.ReadFixed(reader, opts.fixedColumns, input.WidthChars, nil)
+	}
+
+	decoder, err := getEncodingDecoder(opts.encoding)
+	if err != nil {
+		return nil, err
+	}
+	return input.ReadFixed(r, opts.fixedColumns, input.WidthBytes, decoder)
+}
+
+// readFixedColumns returns the fixed-width layout given by --widths or --fixed-spec
+func readFixedColumns(widths, specPath string) ([]input.FixedColumn, error) {
+	if widths != "" {
+		return input.ParseWidths(widths)
+	}
+
+	file, err := os.Open(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixed spec file: %w", err)
+	}
+	defer file.Close()
+
+	columns, err := input.ReadFixedSpec(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixed spec file %s: %w", specPath, err)
+	}
+	return columns, nil
+}
+
+// readJSON reads JSON or NDJSON from r with character encoding conversion
+func readJSON(r io.Reader, format string, opts inputOptions) ([][]string, error) {
+	reader, err := decodeReader(r, opts.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == formatNDJSON {
+		return input.ReadNDJSON(reader, opts.jsonArrays)
+	}
+	return input.ReadJSON(reader, opts.jsonArrays)
+}
+
+// readXLSX reads the worksheets of an xlsx workbook from r, keeping typed values.
+// Empty worksheets are skipped.
+func readXLSX(r io.Reader, opts inputOptions) ([]inputTable, error) {
+	// The zip format needs random access, so the whole workbook is read into memory
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	worksheets, err := input.ReadXLSX(bytes.NewReader(data), int64(len(data)), opts.worksheets)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []inputTable
+	for _, ws := range worksheets {
+		if len(ws.Rows) == 0 {
+			continue
+		}
+		tables = append(tables, inputTable{name: ws.Name, data: ws.Rows, values: ws.Values})
+	}
+	return tables, nil
+}
+
+// readDocument reads the tables of a Markdown or HTML document from r with character
+// encoding conversion. A single table is unnamed like other formats; with several tables,
+// each is named after its caption or heading, or numbered.
+func readDocument(r io.Reader, format string, opts inputOptions) ([]inputTable, error) {
+	reader, err := decodeReader(r, opts.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []input.Table
+	if format == formatHTML {
+		found, err = input.ReadHTMLTables(reader)
+	} else {
+		found, err = input.ReadMarkdownTables(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]inputTable, len(found))
+	for i, table := range found {
+		tables[i].data = table.Rows
+		if len(found) > 1 {
+			tables[i].name = table.Name
+			if tables[i].name == "" {
+				tables[i].name = fmt.Sprintf("Table %d", i+1)
+			}
+		}
+	}
+	return tables, nil
+}
+
+// Modes of --value-input
+const (
+	valueInputRaw         = "raw"
+	valueInputUserEntered = "user-entered"
+	valueInputTyped       = "typed"
+)
+
+// inferTypes converts the cells of the inputs without typed values to values of the
+// types inferred per column, and reports the types on stderr
+func inferTypes(inputs []inputTable) {
+	for i, table := range inputs {
+		if table.values != nil {
+			continue
+		}
+		types := input.InferColumnTypes(table.data)
+		inputs[i].values = input.TypedValues(table.data, types)
+
+		columns := make([]string, len(types))
+		for col, columnType := range types {
+			name := fmt.Sprintf("column %d", col+1)
+			if col < len(table.data[0]) && table.data[0][col] != "" {
+				name = table.data[0][col]
+			}
+			columns[col] = name + "=" + columnType
+		}
+		fmt.Fprintf(os.Stderr, "Column types of %s: %s\n", table.source(), strings.Join(columns, ", "))
+	}
+}
+
+// Policies of --invalid-rows
+const (
+	invalidRowsReject = "reject"
+	invalidRowsMark   = "mark"
+)
+
+// validationErrorsColumn is the header of the column added by --invalid-rows mark
+const validationErrorsColumn = "validation errors"
+
+// readSchemaFile reads the table schema at path
+func readSchemaFile(path string) (*input.Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema file: %w", err)
+	}
+	defer file.Close()
+
+	schema, err := input.ReadSchema(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// applySchema validates the inputs against the schema and converts their cells to typed
+// values with the number formats of the schema. Invalid rows are left out (reject) or
+// kept with their errors in an extra column (mark), and reported on stderr.
+func applySchema(inputs []inputTable, schema *input.Schema, invalidRows string) error {
+	for i, table := range inputs {
+		result, err := schema.Apply(table.data)
+		if err != nil {
+			return fmt.Errorf("failed to validate %s: %w", table.source(), err)
+		}
+
+		data, values := table.data, result.Values
+		if len(result.Invalid) > 0 {
+			fmt.Fprintf(os.Stderr, "Schema validation of %s: %d invalid rows (%s)\n", table.source(), len(result.Invalid), invalidRows)
+			for n, invalid := range result.Invalid {
+				if n == maxReportedLines {
+					fmt.Fprintf(os.Stderr, "  ... (%d more)\n", len(result.Invalid)-maxReportedLines)
+					break
+				}
+				fmt.Fprintf(os.Stderr, "  row %d: %s\n", invalid.Row+1, strings.Join(invalid.Errors, "; "))
+			}
+			if invalidRows == invalidRowsMark {
+				data, values = markInvalidRows(data, values, result.Invalid)
+			} else {
+				data, values = rejectInvalidRows(data, values, result.Invalid)
+			}
+		}
+
+		var formats []sheets.ColumnFormat
+		for col, field := range result.Fields {
+			if field != nil && field.NumberFormat != "" {
+				formats = append(formats, sheets.ColumnFormat{Column: col, Type: numberFormatType(field.Type), Pattern: field.NumberFormat})
+			}
+		}
+		inputs[i].data, inputs[i].values, inputs[i].formats = data, values, formats
+	}
+	return nil
+}
+
+// rejectInvalidRows returns the rows and values without the invalid rows
+func rejectInvalidRows(data [][]string, values [][]interface{}, invalid []input.InvalidRow) ([][]string, [][]interface{}) {
+	skip := make(map[int]bool, len(invalid))
+	for _, row := range invalid {
+		skip[row.Row] = true
+	}
+
+	var keptData [][]string
+	var keptValues [][]interface{}
+	for r := range data {
+		if !skip[r] {
+			keptData = append(keptData, data[r])
+			keptValues = append(keptValues, values[r])
+		}
+	}
+	return keptData, keptValues
+}
+
+// markInvalidRows adds a column after the widest row with the errors of each invalid row
+func markInvalidRows(data [][]string, values [][]interface{}, invalid []input.InvalidRow) ([][]string, [][]interface{}) {
+	width := 0
+	for _, row := range data {
+		width = max(width, len(row))
+	}
+	errs := make(map[int]string, len(invalid))
+	for _, row := range invalid {
+		errs[row.Row] = strings.Join(row.Errors, "; ")
+	}
+
+	for r := range data {
+		cell := errs[r]
+		if r == 0 {
+			cell = validationErrorsColumn
+		}
+		for len(data[r]) < width {
+			data[r] = append(data[r], "")
+			values[r] = append(values[r], nil)
+		}
+		data[r] = append(data[r], cell)
+		values[r] = append(values[r], cell)
+	}
+	return data, values
+}
+
+// numberFormatType returns the Sheets number format type for a schema field type
+func numberFormatType(fieldType string) string {
+	switch fieldType {
+	case input.FieldDate:
+		return "DATE"
+	case input.FieldDateTime:
+		return "DATE_TIME"
+	case input.FieldString, input.FieldAny:
+		return "TEXT"
+	default:
+		return "NUMBER"
+	}
+}
+
+// columnFormat is an entry of --format: a number format pattern for the column with
+// the given header name or letter
+type columnFormat struct {
+	column  string
+	pattern string
+}
+
+// formatEntryPattern matches the start of a --format entry, "column=pattern". Column
+// references cannot contain brackets or quotes, so that a ";" inside a pattern such as
+// "#,##0;[Red]-#,##0" does not start a new entry.
+var formatEntryPattern = regexp.MustCompile(`^\s*([^=\[\]"]+?)\s*=(.*)$`)
+
+// columnLetterPattern matches a column letter such as "B" or "AA"
+var columnLetterPattern = regexp.MustCompile(`^[A-Z]{1,3}$`)
+
+// parseColumnFormats parses the --format value, e.g. "amount=#,##0;date=yyyy-mm-dd".
+// Entries are separated by ";"; text after a ";" that is not "column=" belongs to the
+// pattern of the previous entry.
+func parseColumnFormats(spec string) ([]columnFormat, error) {
+	var formats []columnFormat
+	for _, part := range strings.Split(spec, ";") {
+		if m := formatEntryPattern.FindStringSubmatch(part); m != nil {
+			formats = append(formats, columnFormat{column: m[1], pattern: strings.TrimSpace(m[2])})
+			continue
+		}
+		if len(formats) == 0 {
+			return nil, fmt.Errorf("invalid format %q: must be column=pattern", part)
+		}
+		formats[len(formats)-1].pattern += ";" + part
+	}
+	seen := make(map[string]bool, len(formats))
+	for _, format := range formats {
+		if seen[format.column] {
+			return nil, fmt.Errorf("invalid format: column %q is given more than once", format.column)
+		}
+		seen[format.column] = true
+		if err := checkFormatPattern(format.pattern); err != nil {
+			return nil, fmt.Errorf("invalid format for column %q: %w", format.column, err)
+		}
+	}
+	return formats, nil
+}
+
+// checkFormatPattern checks that a number format pattern is not empty and that its
+// quoted strings and brackets are closed
+func checkFormatPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("pattern is empty")
+	}
+	inQuote, inBracket := false, false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case inQuote:
+			inQuote = c != '"'
+		case c == '\\':
+			i++
+		case c == '"':
+			inQuote = true
+		case c == '[':
+			if inBracket {
+				return fmt.Errorf("nested \"[\" in %q", pattern)
+			}
+			inBracket = true
+		case c == ']':
+			if !inBracket {
+				return fmt.Errorf("unmatched \"]\" in %q", pattern)
+			}
+			inBracket = false
+		}
+	}
+	switch {
+	case inQuote:
+		return fmt.Errorf("unterminated quoted text in %q", pattern)
+	case inBracket:
+		return fmt.Errorf("unterminated \"[\" in %q", pattern)
+	}
+	return nil
+}
+
+// applyColumnFormats adds the number formats of --format to the inputs. Columns are
+// referenced by header name, or else by letter. Two references to the same column,
+// such as its name and its letter, are an error.
+func applyColumnFormats(inputs []inputTable, formats []columnFormat) error {
+	for i, table := range inputs {
+		var header []string
+		if len(table.data) > 0 {
+			header = table.data[0]
+		}
+		refs := make(map[int]string, len(formats))
+		for _, format := range formats {
+			col := -1
+			for c, name := range header {
+				if name == format.column {
+					col = c
+					break
+				}
+			}
+			if col < 0 && columnLetterPattern.MatchString(format.column) {
+				col = columnIndex(format.column)
+			}
+			if col < 0 {
+				return fmt.Errorf("format column %q not found in the header of %s", format.column, table.source())
+			}
+			if ref, ok := refs[col]; ok {
+				return fmt.Errorf("format columns %q and %q are the same column of %s", ref, format.column, table.source())
+			}
+			refs[col] = format.column
+			inputs[i].formats = append(inputs[i].formats, sheets.ColumnFormat{
+				Column:  col,
+				Type:    sheets.NumberFormatType(format.pattern),
+				Pattern: format.pattern,
+			})
+		}
+	}
+	return nil
+}
+
+// columnIndex returns the 0-indexed column of a column letter such as "A" or "AB"
+func columnIndex(letters string) int {
+	col := 0
+	for _, r := range letters {
+		col = col*26 + int(r-'A') + 1
+	}
+	return col - 1
+}
+
+// splitTables splits each table into one table per distinct value of the given header
+// column, in order of first appearance. Every resulting table repeats the header row.
+// When there are several inputs, the table names are prefixed with the input name.
+func splitTables(inputs []inputTable, column string) ([]inputTable, error) {
+	var tables []inputTable
+	for _, input := range inputs {
+		header := input.data[0]
+		idx := -1
+		for i, name := range header {
+			if name == column {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("split-by column %q not found in the header", column)
+		}
+
+		groups := make(map[string]int)
+		var split []inputTable
+		for r, row := range input.data[1:] {
+			value := ""
+			if idx < len(row) {
+				value = row[idx]
+			}
+			i, ok := groups[value]
+			if !ok {
+				i = len(split)
+				groups[value] = i
+				table := inputTable{
+					name:    splitTableName(input.name, value, len(inputs) > 1),
+					data:    [][]string{header},
+					formats: input.formats,
+				}
+				if input.values != nil {
+					table.values = [][]interface{}{input.values[0]}
+				}
+				split = append(split, table)
+			}
+			split[i].data = append(split[i].data, row)
+			if input.values != nil {
+				split[i].values = append(split[i].values, input.values[r+1])
+			}
+		}
+		tables = append(tables, split...)
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no data rows to split")
+	}
+	return tables, nil
+}
+
+// splitTableName returns the sheet name for a group of rows split by value
+func splitTableName(inputName, value string, withPrefix bool) string {
+	name := value
+	if strings.TrimSpace(name) == "" {
+		name = "(blank)"
+	}
+	if withPrefix && inputName != "" {
+		name = inputName + " - " + name
+	}
+	if runes := []rune(name); len(runes) > sheets.MaxSheetNameLength {
+		name = string(runes[:sheets.MaxSheetNameLength])
+	}
+	return name
+}
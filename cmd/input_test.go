@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gs-write/pkg/sheets"
+)
+
+func TestReadCSVMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		opts    inputOptions
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:    "stray quote with a detected dialect",
+			input:   "a,b\n1\" x,2\n",
+			path:    "-",
+			wantErr: "line 2",
+		},
+		{
+			name:    "stray quote with an explicit delimiter",
+			input:   "a;b\n1\" x;2\n",
+			path:    "-",
+			opts:    inputOptions{delimiter: ';'},
+			wantErr: "line 2",
+		},
+		{
+			name:    "stray quote in a tsv file",
+			input:   "a\tb\nx\t1\" y\n",
+			path:    "data.tsv",
+			wantErr: "line 2",
+		},
+		{
+			name:    "ragged row",
+			input:   "a,b\n1,2\n3\n",
+			path:    "-",
+			wantErr: "line 3",
+		},
+		{
+			name:  "lenient repairs stray quotes",
+			input: "a,b\n1\" x,2\n",
+			path:  "-",
+			opts:  inputOptions{lenient: true},
+			want:  [][]string{{"a", "b"}, {"1\" x", "2"}},
+		},
+		{
+			name:  "valid quotes",
+			input: "a,b\n\"1,5\",2\n",
+			path:  "-",
+			want:  [][]string{{"a", "b"}, {"1,5", "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.encoding = "utf-8"
+			got, err := readCSV(strings.NewReader(tt.input), tt.path, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readCSV() error = %v, want an error with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{"escaped tab", `\t`, '\t', false},
+		{"tab name", "tab", '\t', false},
+		{"name in upper case", "TAB", '\t', false},
+		{"semicolon name", "semicolon", ';', false},
+		{"pipe name", "pipe", '|', false},
+		{"space name", "space", ' ', false},
+		{"single character", ";", ';', false},
+		{"multibyte character", "、", '、', false},
+		{"tab character", "\t", '\t', false},
+		{"several characters", "::", 0, true},
+		{"empty", "", 0, true},
+		{"quote", `"`, 0, true},
+		{"newline", "\n", 0, true},
+		{"carriage return", "\r", 0, true},
+		{"invalid utf-8", "\xff", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDelimiter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDelimiter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTables(t *testing.T) {
+	long := strings.Repeat("x", 120)
+	tests := []struct {
+		name      string
+		inputs    []inputTable
+		column    string
+		wantNames []string
+		wantRows  []int
+		wantErr   bool
+	}{
+		{
+			name: "groups in order of first appearance",
+			inputs: []inputTable{{name: "staff", data: [][]string{
+				{"name", "dept"}, {"a", "sales"}, {"b", "dev"}, {"c", "sales"},
+			}}},
+			column:    "dept",
+			wantNames: []string{"sales", "dev"},
+			wantRows:  []int{3, 2},
+		},
+		{
+			name: "empty and missing values",
+			inputs: []inputTable{{name: "staff", data: [][]string{
+				{"name", "dept"}, {"a", ""}, {"b"}, {"c", " "},
+			}}},
+			column:    "dept",
+			wantNames: []string{"(blank)", "(blank)"},
+			wantRows:  []int{3, 2},
+		},
+		{
+			name: "several inputs are prefixed",
+			inputs: []inputTable{
+				{name: "jan", data: [][]string{{"dept"}, {"dev"}}},
+				{name: "feb", data: [][]string{{"dept"}, {"dev"}}},
+			},
+			column:    "dept",
+			wantNames: []string{"jan - dev", "feb - dev"},
+			wantRows:  []int{2, 2},
+		},
+		{
+			name:      "long names are shortened",
+			inputs:    []inputTable{{data: [][]string{{"dept"}, {long}}}},
+			column:    "dept",
+			wantNames: []string{long[:sheets.MaxSheetNameLength]},
+			wantRows:  []int{2},
+		},
+		{
+			name:    "missing column",
+			inputs:  []inputTable{{data: [][]string{{"name"}, {"a"}}}},
+			column:  "dept",
+			wantErr: true,
+		},
+		{
+			name:    "no data rows",
+			inputs:  []inputTable{{data: [][]string{{"dept"}}}},
+			column:  "dept",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := splitTables(tt.inputs, tt.column)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("splitTables() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitTables() error = %v", err)
+			}
+			var names []string
+			var rows []int
+			for _, table := range tables {
+				names = append(names, table.name)
+				rows = append(rows, len(table.data))
+				if !reflect.DeepEqual(table.data[0], tt.inputs[0].data[0]) {
+					t.Errorf("table %q starts with %q, want the header row", table.name, table.data[0])
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) || !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("splitTables() = %q with %v rows, want %q with %v rows", names, rows, tt.wantNames, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestParseColumnFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []columnFormat
+		wantErr bool
+	}{
+		{
+			name: "several columns",
+			spec: "amount=#,##0; date = yyyy-mm-dd ;C=0.00%",
+			want: []columnFormat{{"amount", "#,##0"}, {"date", "yyyy-mm-dd"}, {"C", "0.00%"}},
+		},
+		{
+			name: "sections of a pattern",
+			spec: "amount=#,##0;[Red]-#,##0;B=@",
+			want: []columnFormat{{"amount", "#,##0;[Red]-#,##0"}, {"B", "@"}},
+		},
+		{
+			name: "quoted text and escapes",
+			spec: `price=#,##0" [yen]";B=0\"`,
+			want: []columnFormat{{"price", `#,##0" [yen]"`}, {"B", `0\"`}},
+		},
+		{"missing column", "#,##0", nil, true},
+		{"empty pattern", "amount=", nil, true},
+		{"duplicate column", "amount=0;amount=0.0", nil, true},
+		{"unterminated quote", `amount=0" yen`, nil, true},
+		{"unterminated bracket", "amount=[Red0", nil, true},
+		{"unmatched bracket", "amount=0]", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseColumnFormats(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseColumnFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseColumnFormats() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyColumnFormats(t *testing.T) {
+	header := []string{"name", "amount", "B", "date"}
+	tests := []struct {
+		name    string
+		formats []columnFormat
+		want    []sheets.ColumnFormat
+		wantErr bool
+	}{
+		{
+			name:    "column by name",
+			formats: []columnFormat{{"amount", "#,##0"}, {"date", "yyyy-mm-dd"}},
+			want: []sheets.ColumnFormat{
+				{Column: 1, Type: "NUMBER", Pattern: "#,##0"},
+				{Column: 3, Type: "DATE", Pattern: "yyyy-mm-dd"},
+			},
+		},
+		{
+			name:    "column by letter",
+			formats: []columnFormat{{"A", "@"}, {"D", "hh:mm"}},
+			want: []sheets.ColumnFormat{
+				{Column: 0, Type: "TEXT", Pattern: "@"},
+				{Column: 3, Type: "TIME", Pattern: "hh:mm"},
+			},
+		},
+		{
+			name:    "header names come before letters",
+			formats: []columnFormat{{"B", "0%"}},
+			want:    []sheets.ColumnFormat{{Column: 2, Type: "PERCENT", Pattern: "0%"}},
+		},
+		{"unknown column", []columnFormat{{"price", "0"}}, nil, true},
+		{"lower case letter", []columnFormat{{"a", "0"}}, nil, true},
+		{"same column by name and letter", []columnFormat{{"date", "yyyy"}, {"D", "yyyy"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := []inputTable{{data: [][]string{header, {"a", "1", "x", "2024-01-01"}}}}
+			err := applyColumnFormats(inputs, tt.formats)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyColumnFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(inputs[0].formats, tt.want) {
+				t.Errorf("applyColumnFormats() formats = %+v, want %+v", inputs[0].formats, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		letters string
+		want    int
+	}{
+		{"A", 0},
+		{"Z", 25},
+		{"AA", 26},
+		{"AZ", 51},
+		{"BA", 52},
+		{"ZZ", 701},
+		{"AAA", 702},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.letters, func(t *testing.T) {
+			if got := columnIndex(tt.letters); got != tt.want {
+				t.Errorf("columnIndex(%q) = %d, want %d", tt.letters, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,19 +2,16 @@ package cmd
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"gs-write/pkg/auth"
 	"gs-write/pkg/config"
+	"gs-write/pkg/input"
 	"gs-write/pkg/sheets"
-	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"golang.org/x/text/encoding"
-	"golang.org/x/text/encoding/japanese"
-	"golang.org/x/text/transform"
 )
 
 var (
@@ -30,6 +27,76 @@ var (
 	filterHeaderRowFlag *int
 	// encoding is the character encoding of the input CSV
 	encodingFlag string
+	// spreadsheetFlag is the ID or URL of an existing spreadsheet to append to
+	spreadsheetFlag string
+	// sheetNameFlag is the name of the sheet (tab) to write the data to
+	sheetNameFlag string
+	// ifExistsFlag is the policy when the sheet name already exists in the spreadsheet
+	ifExistsFlag string
+	// upsertKeyFlag is the header name of the key column used to upsert rows
+	upsertKeyFlag string
+	// mirrorFlag makes the target sheet exactly match the input
+	mirrorFlag bool
+	// snapshotFlag adds a timestamped snapshot sheet to the target spreadsheet
+	snapshotFlag bool
+	// keepFlag is the number of snapshot sheets to keep (0 keeps all)
+	keepFlag int
+	// snapshotOrderFlag is the order of snapshot sheets (newest-first or newest-last)
+	snapshotOrderFlag string
+	// splitByFlag is the header name of the column used to split rows into sheets
+	splitByFlag string
+	// delimiterFlag is the field delimiter of the input
+	delimiterFlag string
+	// tsvFlag is a shorthand for --delimiter tab
+	tsvFlag bool
+	// dialectFlag controls dialect detection ("show" prints the detected dialect and exits)
+	dialectFlag string
+	// inputFormatFlag is the format of the input data
+	inputFormatFlag string
+	// columnsModeFlag is how the columns input format splits lines
+	columnsModeFlag string
+	// maxFieldsFlag is the maximum number of fields per line of the columns input format
+	maxFieldsFlag int
+	// jsonArraysFlag is how nested JSON arrays are written
+	jsonArraysFlag string
+	// worksheetFlag is the names of the xlsx worksheets to read
+	worksheetFlag []string
+	// widthsFlag is the comma-separated column widths of the fixed input format
+	widthsFlag string
+	// fixedSpecFlag is the path of the column spec file of the fixed input format
+	fixedSpecFlag string
+	// widthUnitFlag is the unit of the fixed input format widths
+	widthUnitFlag string
+	// lenientFlag repairs malformed CSV rows instead of failing
+	lenientFlag bool
+	// strictFlag states the default parsing explicitly: malformed CSV rows fail
+	strictFlag bool
+	// skipMalformedFlag skips malformed CSV rows in lenient mode
+	skipMalformedFlag bool
+	// streamFlag uploads CSV input in chunks while reading it
+	streamFlag bool
+	// chunkRowsFlag is the maximum number of rows per request when streaming
+	chunkRowsFlag int
+	// chunkBytesFlag is the approximate maximum size of a request when streaming
+	chunkBytesFlag int
+	// overflowFlag is the policy when the data exceeds the size limits of a spreadsheet
+	overflowFlag string
+	// valueInputFlag is how cell values are written (raw, user-entered or typed)
+	valueInputFlag string
+	// schemaFlag is the path of the table schema the input is validated against
+	schemaFlag string
+	// invalidRowsFlag is the policy for rows that fail schema validation (reject or mark)
+	invalidRowsFlag string
+	// formulaPolicyFlag is how cells that would be read as formulas are written
+	formulaPolicyFlag string
+	// headerStyleFlag is the preset of the header row style
+	headerStyleFlag string
+	// autoResizeFlag fits the width of the columns to their content
+	autoResizeFlag bool
+	// maxColWidthFlag is the maximum width in pixels of auto-resized columns (0 means no limit)
+	maxColWidthFlag int
+	// formatFlag is the number formats of columns, e.g. "amount=#,##0;date=yyyy-mm-dd"
+	formatFlag string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -45,10 +112,41 @@ It works seamlessly with pipes (|) to connect with other commands.
 Examples / 使用例:
   ls -l | gs-write
   cat report.csv | gs-write --title "Monthly Report"
+  gs-write --title "Monthly Report" sales.csv costs.csv staff.tsv
+  cat employees.csv | gs-write --split-by department --freeze-rows 1
   cat data.csv | gs-write --freeze-rows 1 --freeze-cols 0
   cat data.csv | gs-write --filter-header-row 1
+  cat data.csv | gs-write --freeze-rows 1 --header-style dark
+  ls -l | gs-write --auto-resize --max-col-width 400
   cat data.csv | gs-write --encoding sjis
-  ps aux | gs-write --title "Processes" --freeze-rows 1 --filter-header-row 1`,
+  mysql -B -e "SELECT * FROM users" | gs-write --tsv
+  cat data.txt | gs-write --delimiter ";"
+  cat data.txt | gs-write --dialect show
+  cat broken.csv | gs-write --lenient
+  cat broken.csv | gs-write --lenient --skip-malformed
+  zcat huge.csv.gz | gs-write --stream --freeze-rows 1
+  cat access.log.csv | gs-write --overflow split-files
+  cat sales.csv | gs-write --value-input typed
+  cat scraped.csv | gs-write --value-input user-entered --formula-policy reject
+  cat sales.csv | gs-write --schema schema.json --invalid-rows mark
+  cat sales.csv | gs-write --value-input typed --format "amount=#,##0;date=yyyy-mm-dd;rate=0.00%"
+  ps aux | gs-write --title "Processes" --freeze-rows 1 --filter-header-row 1
+  ps aux | gs-write --input-format columns --max-fields 11
+  df -h | gs-write --input-format columns --columns-mode header
+  kubectl get pods -o json | jq '.items' | gs-write --input-format json
+  cat events.ndjson | gs-write --input-format ndjson --json-arrays explode
+  gs-write --title "Budget" budget.xlsx
+  cat budget.xlsx | gs-write --worksheet Summary
+  gs-write --input-format markdown README.md
+  cat report.dat | gs-write --input-format fixed --widths 10,8,20 --encoding sjis
+  gs-write --input-format fixed --fixed-spec layout.txt --encoding sjis report.dat
+  curl -s https://example.com/stats.html | gs-write --input-format html
+  cat today.csv | gs-write --spreadsheet https://docs.google.com/spreadsheets/d/xxxx/edit
+  cat today.csv | gs-write --spreadsheet xxxx --sheet-name "2024-06" --if-exists suffix
+  cat inventory.csv | gs-write --spreadsheet xxxx --upsert-key id
+  cat dashboard.csv | gs-write --spreadsheet xxxx --sheet-name dashboard --mirror
+  cat daily.csv | gs-write --spreadsheet xxxx --snapshot --keep 30`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runRoot,
 }
 
@@ -69,9 +167,76 @@ func init() {
 	freezeRowsFlag = rootCmd.Flags().Int("freeze-rows", -1, "Number of rows to freeze / 固定する行数 (overrides config file / 設定ファイルを上書き)")
 	freezeColsFlag = rootCmd.Flags().Int("freeze-cols", -1, "Number of columns to freeze / 固定する列数 (overrides config file / 設定ファイルを上書き)")
 	filterHeaderRowFlag = rootCmd.Flags().Int("filter-header-row", -1, "Header row for basic filter / フィルタのヘッダー行 (overrides config file / 設定ファイルを上書き)")
+	rootCmd.Flags().StringVar(&headerStyleFlag, "header-style", sheets.HeaderStyleNone, "Style preset of the header row (the filter header row, otherwise the first row) / ヘッダー行 (フィルタのヘッダー行、なければ1行目) のスタイル (none, bold, bold+background, dark) (overrides config file / 設定ファイルを上書き)")
+	rootCmd.Flags().BoolVar(&autoResizeFlag, "auto-resize", false, "Fit the width of the columns to their content / 列幅を内容に合わせる (overrides config file / 設定ファイルを上書き)")
+	rootCmd.Flags().IntVar(&maxColWidthFlag, "max-col-width", 0, "Maximum width in pixels of auto-resized columns / 自動調整した列幅の上限 (ピクセル) (0: unlimited / 無制限) (overrides config file / 設定ファイルを上書き)")
 
 	// Add encoding flag
-	rootCmd.Flags().StringVar(&encodingFlag, "encoding", "utf-8", "Character encoding of input CSV / 入力CSVの文字エンコーディング (utf-8, sjis, euc-jp)")
+	rootCmd.Flags().StringVar(&encodingFlag, "encoding", encodingAuto, "Character encoding of input; run \"gs-write encodings\" for the list / 入力の文字エンコーディング、一覧は\"gs-write encodings\"で表示 (auto: detected from the input / 入力から自動検出) (overrides config file / 設定ファイルを上書き)")
+
+	// Add input format flags
+	rootCmd.Flags().StringVar(&inputFormatFlag, "input-format", "", "Format of input data / 入力データの形式 (csv, columns, json, ndjson, xlsx, markdown, html, fixed) (default: by file extension, otherwise csv / デフォルト: 拡張子から判定、それ以外はcsv)")
+	rootCmd.Flags().StringVar(&columnsModeFlag, "columns-mode", columnsModeWhitespace, "How the columns format splits lines / columns形式の分割方法 (whitespace: runs of whitespace / 連続する空白, header: column positions of the first line / 1行目の列位置)")
+	rootCmd.Flags().StringVar(&jsonArraysFlag, "json-arrays", input.ArraysSerialize, "How nested JSON arrays are written / JSONの配列の書き込み方法 (serialize: as JSON text in one cell / 1セルにJSONテキストで, explode: one row per element / 要素ごとに1行)")
+	rootCmd.Flags().StringSliceVar(&worksheetFlag, "worksheet", nil, "Name of the xlsx worksheet to read; can be repeated / 読み込むxlsxワークシートの名前、複数指定可 (default: all worksheets / デフォルト: すべてのワークシート)")
+	rootCmd.Flags().StringVar(&widthsFlag, "widths", "", "Comma-separated column widths for the fixed format, e.g. 10,8,20 / fixed形式の列幅をカンマ区切りで指定")
+	rootCmd.Flags().StringVar(&fixedSpecFlag, "fixed-spec", "", "Path of a column spec file for the fixed format (\"name width\" per line) / fixed形式の列定義ファイルのパス (1行に\"列名 幅\")")
+	rootCmd.Flags().StringVar(&widthUnitFlag, "width-unit", input.WidthBytes, "Unit of the fixed format widths / fixed形式の列幅の単位 (bytes: bytes before decoding / デコード前のバイト数, chars: characters / 文字数)")
+	rootCmd.Flags().IntVar(&maxFieldsFlag, "max-fields", 0, "Maximum number of fields per line for the columns format; the last field keeps the rest / columns形式の1行あたりの最大フィールド数、最後のフィールドに残りを含める (0: unlimited / 無制限)")
+
+	// Add delimiter flags
+	rootCmd.Flags().StringVar(&delimiterFlag, "delimiter", "", "Field delimiter of input / 入力の区切り文字 (single character or tab, comma, semicolon, pipe, space / 1文字または名前) (overrides config file / 設定ファイルを上書き)")
+	rootCmd.Flags().BoolVar(&tsvFlag, "tsv", false, "Read input as tab-separated values (same as --delimiter tab) / 入力をタブ区切りとして読み込む (--delimiter tabと同じ)")
+
+	// Add CSV parsing mode flags
+	rootCmd.Flags().BoolVar(&lenientFlag, "lenient", false, "Accept malformed CSV: stray quotes, ragged rows (short rows are padded) / 不正なCSVを受け付ける: 不正な引用符、列数の不揃いな行 (短い行は空セルで補完)")
+	rootCmd.Flags().BoolVar(&strictFlag, "strict", false, "Fail with the line number on malformed CSV rows, as without --lenient (cannot be used with --lenient) / 不正なCSVの行で行番号を表示してエラーにする。--lenientなしと同じ動作 (--lenientとは併用不可)")
+	rootCmd.Flags().BoolVar(&skipMalformedFlag, "skip-malformed", false, "With --lenient, skip malformed rows instead of repairing them / --lenient指定時、不正な行を補完せずスキップ")
+
+	// Add streaming flags
+	rootCmd.Flags().BoolVar(&streamFlag, "stream", false, "Upload CSV input in chunks while reading it, for very large inputs / CSV入力を読み込みながら分割してアップロード (巨大な入力向け)")
+	rootCmd.Flags().IntVar(&chunkRowsFlag, "chunk-rows", sheets.DefaultChunkRows, "Maximum number of rows per request with --stream / --stream指定時の1リクエストあたりの最大行数")
+	rootCmd.Flags().IntVar(&chunkBytesFlag, "chunk-bytes", sheets.DefaultChunkBytes, "Approximate maximum bytes per request with --stream / --stream指定時の1リクエストあたりの最大バイト数 (目安)")
+
+	// Add overflow flag
+	rootCmd.Flags().StringVar(&overflowFlag, "overflow", sheets.OverflowFail, "Policy when the data exceeds the spreadsheet size limits (10M cells, 50,000 characters per cell) / データがスプレッドシートのサイズ上限 (1000万セル、1セル5万文字) を超える場合の動作 (fail, truncate, split-sheets, split-files). Both split policies continue in new spreadsheets and differ only in the sheet names: \"Sheet1 (2)\" with split-sheets, \"Sheet1\" with split-files / split-sheetsとsplit-filesはどちらも新しいスプレッドシートに続きを書き込み、シート名のみ異なる (split-sheets: \"Sheet1 (2)\", split-files: \"Sheet1\")")
+
+	// Add value input flag
+	rootCmd.Flags().StringVar(&valueInputFlag, "value-input", valueInputRaw, "How cell values are written / セルの値の書き込み方法 (raw: as text / テキストのまま, user-entered: parsed like typed in the UI / 画面で入力したように解釈, typed: types inferred per column / 列ごとに型を推定)")
+
+	// Add column format flag
+	rootCmd.Flags().StringVar(&formatFlag, "format", "", "Number formats of columns by header name or letter, e.g. \"amount=#,##0;date=yyyy-mm-dd;C=0.00%\" (requires --value-input user-entered or typed, --schema or xlsx input) / 列の表示形式をヘッダー名または列記号で指定 (--value-input user-entered, typed, --schema またはxlsxの入力が必要)")
+
+	// Add formula policy flag
+	rootCmd.Flags().StringVar(&formulaPolicyFlag, "formula-policy", sheets.FormulaEscape, "How cells starting with =, +, - or @ are written / =, +, -, @で始まるセルの書き込み方法 (escape: as text with user-entered / user-entered時にテキストとして, allow: as they are / そのまま, reject: fail / エラーにする)")
+
+	// Add schema flags
+	rootCmd.Flags().StringVar(&schemaFlag, "schema", "", "Path of a Frictionless Table Schema (JSON) to validate and type the input / 入力を検証し型を付けるFrictionless Table Schema (JSON) のパス")
+	rootCmd.Flags().StringVar(&invalidRowsFlag, "invalid-rows", invalidRowsReject, "Policy for rows that fail schema validation / スキーマの検証に失敗した行の扱い (reject: leave out / 除外, mark: keep with errors in an extra column / エラーを追加の列に記載して残す)")
+
+	// Add dialect flag
+	rootCmd.Flags().StringVar(&dialectFlag, "dialect", "", "Set to \"show\" to print the detected delimiter and quoting without uploading / \"show\"を指定すると検出した区切り文字と引用符を表示して終了 (アップロードしない)")
+
+	// Add spreadsheet flag
+	rootCmd.Flags().StringVar(&spreadsheetFlag, "spreadsheet", "", "ID or URL of an existing spreadsheet to append to / 追記先の既存スプレッドシートのIDまたはURL")
+
+	// Add sheet name flags
+	rootCmd.Flags().StringVar(&sheetNameFlag, "sheet-name", "", "Name of the sheet (tab) to create / 作成するシート(タブ)の名前 (with --spreadsheet, adds a new tab / --spreadsheet指定時は新しいタブを追加)")
+	rootCmd.Flags().StringVar(&ifExistsFlag, "if-exists", sheets.IfExistsFail, "Policy when the sheet name already exists / シート名が既に存在する場合の動作 (fail, overwrite, suffix)")
+
+	// Add upsert flag
+	rootCmd.Flags().StringVar(&upsertKeyFlag, "upsert-key", "", "Header name of the key column to upsert rows into an existing sheet (requires --spreadsheet) / 既存シートに行をupsertするキー列のヘッダー名 (--spreadsheetが必要)")
+
+	// Add mirror flag
+	rootCmd.Flags().BoolVar(&mirrorFlag, "mirror", false, "Make an existing sheet exactly match the input (requires --spreadsheet) / 既存シートを入力と完全に一致させる (--spreadsheetが必要)")
+
+	// Add snapshot flags
+	rootCmd.Flags().BoolVar(&snapshotFlag, "snapshot", false, "Add a timestamped snapshot sheet (requires --spreadsheet) / タイムスタンプ名のスナップショットシートを追加 (--spreadsheetが必要)")
+	rootCmd.Flags().IntVar(&keepFlag, "keep", 0, "Number of snapshot sheets to keep, deleting the oldest / 保持するスナップショットシート数、古いものから削除 (0: keep all / すべて保持)")
+	rootCmd.Flags().StringVar(&snapshotOrderFlag, "snapshot-order", sheets.SnapshotNewestLast, "Order of snapshot sheets / スナップショットシートの並び順 (newest-first, newest-last)")
+
+	// Add split flag
+	rootCmd.Flags().StringVar(&splitByFlag, "split-by", "", "Header name of the column whose values split rows into separate sheets / 値ごとに行を別シートに分割する列のヘッダー名")
 
 	// Disable completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
@@ -79,6 +244,7 @@ func init() {
 	// Add subcommands
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(encodingsCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -95,6 +261,12 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	freezeRows := resolveFreezeRows(cmd, userConfig)
 	freezeCols := resolveFreezeCols(cmd, userConfig)
 	filterHeaderRow := resolveFilterHeaderRow(cmd, userConfig)
+	headerStyle, err := resolveHeaderStyle(cmd, userConfig)
+	if err != nil {
+		return err
+	}
+	autoResize := resolveAutoResize(cmd, userConfig)
+	maxColWidth := resolveMaxColWidth(cmd, userConfig)
 
 	// Validate parameters
 	if freezeRows < 0 || freezeCols < 0 {
@@ -103,6 +275,245 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	if filterHeaderRow < 0 {
 		return fmt.Errorf("filter-header-row must be non-negative (got: %d)", filterHeaderRow)
 	}
+	if maxColWidth < 0 {
+		return fmt.Errorf("max-col-width must be non-negative (got: %d)", maxColWidth)
+	}
+	if cmd.Flags().Changed("max-col-width") && !autoResize {
+		return fmt.Errorf("--max-col-width requires --auto-resize")
+	}
+	if spreadsheetFlag != "" && title != "" {
+		return fmt.Errorf("--title cannot be used with --spreadsheet")
+	}
+	if upsertKeyFlag != "" && spreadsheetFlag == "" {
+		return fmt.Errorf("--upsert-key requires --spreadsheet")
+	}
+	if mirrorFlag && spreadsheetFlag == "" {
+		return fmt.Errorf("--mirror requires --spreadsheet")
+	}
+	if mirrorFlag && upsertKeyFlag != "" {
+		return fmt.Errorf("--mirror cannot be used with --upsert-key")
+	}
+	if snapshotFlag {
+		if spreadsheetFlag == "" {
+			return fmt.Errorf("--snapshot requires --spreadsheet")
+		}
+		if sheetNameFlag != "" || upsertKeyFlag != "" || mirrorFlag {
+			return fmt.Errorf("--snapshot cannot be used with --sheet-name, --upsert-key or --mirror")
+		}
+	}
+	if keepFlag < 0 {
+		return fmt.Errorf("keep must be non-negative (got: %d)", keepFlag)
+	}
+	switch snapshotOrderFlag {
+	case sheets.SnapshotNewestFirst, sheets.SnapshotNewestLast:
+	default:
+		return fmt.Errorf("invalid snapshot order: %s (supported: newest-first, newest-last)", snapshotOrderFlag)
+	}
+	if (len(args) > 1 || splitByFlag != "") && (sheetNameFlag != "" || upsertKeyFlag != "" || mirrorFlag || snapshotFlag) {
+		return fmt.Errorf("--sheet-name, --upsert-key, --mirror and --snapshot require a single input and cannot be used with --split-by")
+	}
+	switch ifExistsFlag {
+	case sheets.IfExistsFail, sheets.IfExistsOverwrite, sheets.IfExistsSuffix:
+	default:
+		return fmt.Errorf("invalid if-exists policy: %s (supported: fail, overwrite, suffix)", ifExistsFlag)
+	}
+
+	delimiter, err := resolveDelimiter(cmd, userConfig)
+	if err != nil {
+		return err
+	}
+	encodingName, err := resolveEncodingName(cmd, userConfig)
+	if err != nil {
+		return err
+	}
+	switch inputFormatFlag {
+	case "", formatCSV, formatColumns, formatJSON, formatNDJSON, formatXLSX, formatMarkdown, formatHTML, formatFixed:
+	default:
+		return fmt.Errorf("invalid input format: %s (supported: csv, columns, json, ndjson, xlsx, markdown, html, fixed)", inputFormatFlag)
+	}
+	var fixedColumns []input.FixedColumn
+	if inputFormatFlag == formatFixed {
+		if (widthsFlag == "") == (fixedSpecFlag == "") {
+			return fmt.Errorf("--input-format fixed requires either --widths or --fixed-spec")
+		}
+		fixedColumns, err = readFixedColumns(widthsFlag, fixedSpecFlag)
+		if err != nil {
+			return err
+		}
+	} else if widthsFlag != "" || fixedSpecFlag != "" {
+		return fmt.Errorf("--widths and --fixed-spec can only be used with --input-format fixed")
+	}
+	switch widthUnitFlag {
+	case input.WidthBytes, input.WidthChars:
+	default:
+		return fmt.Errorf("invalid width unit: %s (supported: bytes, chars)", widthUnitFlag)
+	}
+	if len(worksheetFlag) > 0 && inputFormatFlag != "" && inputFormatFlag != formatXLSX {
+		return fmt.Errorf("--worksheet can only be used with xlsx input")
+	}
+	switch jsonArraysFlag {
+	case input.ArraysSerialize, input.ArraysExplode:
+	default:
+		return fmt.Errorf("invalid json-arrays mode: %s (supported: serialize, explode)", jsonArraysFlag)
+	}
+	switch columnsModeFlag {
+	case columnsModeWhitespace, columnsModeHeader:
+	default:
+		return fmt.Errorf("invalid columns mode: %s (supported: whitespace, header)", columnsModeFlag)
+	}
+	if maxFieldsFlag < 0 {
+		return fmt.Errorf("max-fields must be non-negative (got: %d)", maxFieldsFlag)
+	}
+	if inputFormatFlag != "" && inputFormatFlag != formatCSV && (cmd.Flags().Changed("delimiter") || tsvFlag || dialectFlag != "" || lenientFlag) {
+		return fmt.Errorf("--delimiter, --tsv, --dialect and --lenient can only be used with --input-format csv")
+	}
+	if lenientFlag && strictFlag {
+		return fmt.Errorf("--lenient cannot be used with --strict")
+	}
+	if skipMalformedFlag && !lenientFlag {
+		return fmt.Errorf("--skip-malformed requires --lenient")
+	}
+	if streamFlag {
+		if spreadsheetFlag != "" || splitByFlag != "" || len(args) > 1 || lenientFlag {
+			return fmt.Errorf("--stream requires a single input and cannot be used with --spreadsheet, --split-by or --lenient")
+		}
+		if inputFormatFlag != "" && inputFormatFlag != formatCSV {
+			return fmt.Errorf("--stream can only be used with --input-format csv")
+		}
+	} else if cmd.Flags().Changed("chunk-rows") || cmd.Flags().Changed("chunk-bytes") {
+		return fmt.Errorf("--chunk-rows and --chunk-bytes require --stream")
+	}
+	switch overflowFlag {
+	case sheets.OverflowFail, sheets.OverflowTruncate, sheets.OverflowSplitSheets, sheets.OverflowSplitFiles:
+	default:
+		return fmt.Errorf("invalid overflow policy: %s (supported: fail, truncate, split-sheets, split-files)", overflowFlag)
+	}
+	if cmd.Flags().Changed("overflow") && (spreadsheetFlag != "" || streamFlag) {
+		return fmt.Errorf("--overflow cannot be used with --spreadsheet or --stream")
+	}
+	switch valueInputFlag {
+	case valueInputRaw, valueInputUserEntered, valueInputTyped:
+	default:
+		return fmt.Errorf("invalid value input: %s (supported: raw, user-entered, typed)", valueInputFlag)
+	}
+	if valueInputFlag == valueInputTyped && (streamFlag || upsertKeyFlag != "" || mirrorFlag || snapshotFlag) {
+		return fmt.Errorf("--value-input typed cannot be used with --stream, --upsert-key, --mirror or --snapshot")
+	}
+	if valueInputFlag == valueInputUserEntered && (upsertKeyFlag != "" || mirrorFlag) {
+		// The values read back to compare rows are formatted by Sheets, e.g. "1.50" becomes "1.5"
+		return fmt.Errorf("--value-input user-entered cannot be used with --upsert-key or --mirror")
+	}
+	switch formulaPolicyFlag {
+	case sheets.FormulaEscape, sheets.FormulaAllow, sheets.FormulaReject:
+	default:
+		return fmt.Errorf("invalid formula policy: %s (supported: escape, allow, reject)", formulaPolicyFlag)
+	}
+	switch invalidRowsFlag {
+	case invalidRowsReject, invalidRowsMark:
+	default:
+		return fmt.Errorf("invalid invalid-rows policy: %s (supported: reject, mark)", invalidRowsFlag)
+	}
+	var schema *input.Schema
+	if schemaFlag != "" {
+		if streamFlag || upsertKeyFlag != "" || mirrorFlag || snapshotFlag || valueInputFlag == valueInputTyped {
+			return fmt.Errorf("--schema cannot be used with --stream, --upsert-key, --mirror, --snapshot or --value-input typed")
+		}
+		schema, err = readSchemaFile(schemaFlag)
+		if err != nil {
+			return err
+		}
+	} else if cmd.Flags().Changed("invalid-rows") {
+		return fmt.Errorf("--invalid-rows requires --schema")
+	}
+	var columnFormats []columnFormat
+	if formatFlag != "" {
+		if streamFlag || upsertKeyFlag != "" || mirrorFlag || snapshotFlag {
+			return fmt.Errorf("--format cannot be used with --stream, --upsert-key, --mirror or --snapshot")
+		}
+		columnFormats, err = parseColumnFormats(formatFlag)
+		if err != nil {
+			return err
+		}
+	}
+	if chunkRowsFlag <= 0 || chunkBytesFlag <= 0 {
+		return fmt.Errorf("chunk-rows and chunk-bytes must be positive (got: rows=%d, bytes=%d)", chunkRowsFlag, chunkBytesFlag)
+	}
+	inputOpts := inputOptions{
+		format:        inputFormatFlag,
+		encoding:      encodingName,
+		delimiter:     delimiter,
+		columnsMode:   columnsModeFlag,
+		maxFields:     maxFieldsFlag,
+		jsonArrays:    jsonArraysFlag,
+		worksheets:    worksheetFlag,
+		fixedColumns:  fixedColumns,
+		widthUnit:     widthUnitFlag,
+		lenient:       lenientFlag,
+		skipMalformed: skipMalformedFlag,
+	}
+
+	// Print the detected dialect without uploading
+	switch dialectFlag {
+	case "":
+	case "show":
+		return showDialects(args, inputOpts)
+	default:
+		return fmt.Errorf("invalid dialect: %s (supported: show)", dialectFlag)
+	}
+
+	opts := sheets.SheetOptions{
+		FreezeRows:      freezeRows,
+		FreezeCols:      freezeCols,
+		FilterHeaderRow: filterHeaderRow,
+		HeaderStyle:     headerStyle,
+		AutoResize:      autoResize,
+		MaxColWidth:     maxColWidth,
+	}
+
+	// Upload while reading instead of loading the whole input into memory
+	if streamFlag {
+		return runStream(ctx, args, inputOpts, opts)
+	}
+
+	// Read input data from files or stdin with encoding conversion
+	inputs, err := readInputs(args, inputOpts)
+	if err != nil {
+		return err
+	}
+	if len(inputs) > 1 && (sheetNameFlag != "" || upsertKeyFlag != "" || mirrorFlag || snapshotFlag) {
+		return fmt.Errorf("--sheet-name, --upsert-key, --mirror and --snapshot require a single input; use --worksheet to select one worksheet")
+	}
+
+	// Validate and type the cells by the schema, or by the types inferred per column
+	if schema != nil {
+		if err := applySchema(inputs, schema, invalidRowsFlag); err != nil {
+			return err
+		}
+	} else if valueInputFlag == valueInputTyped {
+		inferTypes(inputs)
+	}
+
+	// Add the number formats of the columns, after those of the schema so that they take precedence.
+	// Formats have no effect on text, so raw input needs typed values such as those of xlsx.
+	if len(columnFormats) > 0 && valueInputFlag == valueInputRaw {
+		for _, table := range inputs {
+			if table.values == nil {
+				return fmt.Errorf("--format requires --value-input user-entered or typed, --schema or xlsx input, so that numbers and dates are written as values (%s has text only)", table.source())
+			}
+		}
+	}
+	if err := applyColumnFormats(inputs, columnFormats); err != nil {
+		return err
+	}
+
+	// Refuse formulas before anything is uploaded
+	if formulaPolicyFlag == sheets.FormulaReject {
+		for _, table := range inputs {
+			if err := sheets.CheckFormulas(table.data); err != nil {
+				return fmt.Errorf("%w in %s (use --formula-policy escape to write it as text)", err, table.source())
+			}
+		}
+	}
 
 	// Load authentication config
 	oauthConfig, token, err := auth.GetClient(ctx)
@@ -115,69 +526,181 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if valueInputFlag == valueInputUserEntered {
+		client.SetValueInputOption(sheets.ValueInputUserEntered)
+	}
+	client.SetFormulaPolicy(formulaPolicyFlag)
 
-	// Read CSV data from stdin with encoding conversion
-	data, err := readCSVFromStdin(encodingFlag)
-	if err != nil {
-		return fmt.Errorf("failed to read CSV from stdin: %w", err)
+	// Split rows into one table per column value
+	if splitByFlag != "" {
+		inputs, err = splitTables(inputs, splitByFlag)
+		if err != nil {
+			return err
+		}
 	}
 
-	if len(data) == 0 {
-		return fmt.Errorf("no data provided")
+	if spreadsheetFlag != "" {
+		urls, err := writeToExistingSpreadsheet(ctx, client, inputs, opts)
+		if err != nil {
+			return err
+		}
+
+		// Output the URLs
+		for _, url := range urls {
+			fmt.Println(url)
+		}
+		return nil
 	}
 
-	// Create spreadsheet
-	url, err := client.CreateSpreadsheet(ctx, title, data, freezeRows, freezeCols, filterHeaderRow)
+	// Create spreadsheet with one sheet per input
+	sheetList := make([]sheets.Sheet, len(inputs))
+	for i, input := range inputs {
+		name := input.name
+		if sheetNameFlag != "" {
+			name = sheetNameFlag
+		}
+		sheetList[i] = input.sheet(name, opts)
+	}
+	result, err := client.CreateSpreadsheets(ctx, title, sheetList, overflowFlag)
 	if err != nil {
+		if errors.Is(err, sheets.ErrTooLarge) {
+			return fmt.Errorf("%w (use --overflow split-sheets, split-files or truncate)", err)
+		}
 		return err
 	}
+	if result.TruncatedCells > 0 {
+		fmt.Fprintf(os.Stderr, "Overflow: %d cells truncated to %d characters\n", result.TruncatedCells, sheets.MaxCellChars)
+	}
+	if result.DroppedRows > 0 {
+		fmt.Fprintf(os.Stderr, "Overflow: %d rows dropped to stay within %d cells\n", result.DroppedRows, sheets.MaxCells)
+	}
+	if len(result.URLs) > 1 {
+		fmt.Fprintf(os.Stderr, "Overflow: split into %d spreadsheets\n", len(result.URLs))
+	}
 
-	// Output the URL
-	fmt.Println(url)
+	// Output the URLs
+	for _, url := range result.URLs {
+		fmt.Println(url)
+	}
 
 	return nil
 }
 
-// readCSVFromStdin reads CSV data from standard input with character encoding conversion
-func readCSVFromStdin(encodingName string) ([][]string, error) {
-	// Get the decoder for the specified encoding
-	decoder, err := getEncodingDecoder(encodingName)
+// runStream reads the single CSV input row by row and uploads it in chunks to a new spreadsheet
+func runStream(ctx context.Context, args []string, inputOpts inputOptions, opts sheets.SheetOptions) error {
+	path := "-"
+	if len(args) == 1 {
+		path = args[0]
+	}
+	rows, closer, err := openCSVStream(path, inputOpts)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer closer.Close()
 
-	// Create a reader with encoding conversion
-	var reader io.Reader
-	if decoder != nil {
-		// Convert from the specified encoding to UTF-8
-		reader = transform.NewReader(os.Stdin, decoder)
-	} else {
-		// No conversion needed for UTF-8
-		reader = os.Stdin
+	// Load authentication config
+	oauthConfig, token, err := auth.GetClient(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Parse CSV
-	csvReader := csv.NewReader(reader)
-	records, err := csvReader.ReadAll()
+	// Create Sheets client
+	client, err := sheets.NewClient(ctx, oauthConfig, token)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if valueInputFlag == valueInputUserEntered {
+		client.SetValueInputOption(sheets.ValueInputUserEntered)
+	}
+	client.SetFormulaPolicy(formulaPolicyFlag)
+
+	sheetName := sheetNameFlag
+	if sheetName == "" && path != "-" {
+		sheetName = tableName(path)
+	}
+	stream := sheets.StreamOptions{
+		ChunkRows:  chunkRowsFlag,
+		ChunkBytes: chunkBytesFlag,
+		Progress:   printProgress,
+	}
+	url, err := client.CreateSpreadsheetFromStream(ctx, title, sheetName, rows, opts, stream)
+	if err != nil {
+		return err
 	}
-	return records, nil
+
+	// Output the URL
+	fmt.Println(url)
+	return nil
 }
 
-// getEncodingDecoder returns the decoder for the specified encoding name
-func getEncodingDecoder(encodingName string) (*encoding.Decoder, error) {
-	switch encodingName {
-	case "utf-8", "utf8", "UTF-8", "UTF8":
-		// No conversion needed for UTF-8
-		return nil, nil
-	case "sjis", "shift-jis", "shift_jis", "SJIS", "Shift-JIS", "Shift_JIS":
-		return japanese.ShiftJIS.NewDecoder(), nil
-	case "euc-jp", "euc_jp", "eucjp", "EUC-JP", "EUC_JP", "EUCJP":
-		return japanese.EUCJP.NewDecoder(), nil
+// writeToExistingSpreadsheet writes the inputs to the spreadsheet given by --spreadsheet
+// according to the selected mode and returns the URLs of the sheets written
+func writeToExistingSpreadsheet(ctx context.Context, client *sheets.Client, inputs []inputTable, opts sheets.SheetOptions) ([]string, error) {
+	spreadsheetID, err := sheets.ParseSpreadsheetID(spreadsheetFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Named inputs (files) are added as new sheets named after them
+	if sheetNameFlag == "" && upsertKeyFlag == "" && !mirrorFlag && !snapshotFlag && inputs[0].name != "" {
+		var urls []string
+		for _, input := range inputs {
+			url, err := client.AddSheetToSpreadsheet(ctx, spreadsheetID, input.sheet(input.name, opts), ifExistsFlag)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, url)
+		}
+		return urls, nil
+	}
+
+	data := inputs[0].data
+	var url string
+	switch {
+	case upsertKeyFlag != "":
+		// Upsert rows into an existing sheet by key column
+		result, err := client.UpsertToSpreadsheet(ctx, spreadsheetID, sheetNameFlag, upsertKeyFlag, data, opts)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Upsert: %d updated, %d appended, %d unchanged\n", result.Updated, result.Appended, result.Unchanged)
+		url = result.URL
+	case snapshotFlag:
+		// Add a snapshot sheet and prune old ones
+		result, err := client.AddSnapshotSheet(ctx, spreadsheetID, data, keepFlag, snapshotOrderFlag, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range result.Pruned {
+			fmt.Fprintf(os.Stderr, "Deleted old snapshot: %s\n", name)
+		}
+		url = result.URL
+	case mirrorFlag:
+		// Make an existing sheet exactly match the input
+		result, err := client.MirrorToSpreadsheet(ctx, spreadsheetID, sheetNameFlag, data, opts)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Mirror: %d updated, %d appended, %d deleted\n", result.Updated, result.Appended, result.Deleted)
+		url = result.URL
+	case sheetNameFlag != "":
+		// Add a new sheet to an existing spreadsheet
+		url, err = client.AddSheetToSpreadsheet(ctx, spreadsheetID, inputs[0].sheet(sheetNameFlag, opts), ifExistsFlag)
+		if err != nil {
+			return nil, err
+		}
 	default:
-		return nil, fmt.Errorf("unsupported encoding: %s (supported: utf-8, sjis, euc-jp)", encodingName)
+		// Append to an existing spreadsheet
+		if valueInputFlag == valueInputTyped || schemaFlag != "" || formatFlag != "" {
+			return nil, fmt.Errorf("--value-input typed, --schema and --format cannot be used when appending; use --sheet-name to add a new sheet")
+		}
+		url, err = client.AppendToSpreadsheet(ctx, spreadsheetID, data, opts)
+		if err != nil {
+			return nil, err
+		}
 	}
+
+	return []string{url}, nil
 }
 
 // resolveFreezeRows determines the freeze rows value with priority: CLI > config > default
@@ -228,6 +751,141 @@ func resolveFilterHeaderRow(cmd *cobra.Command, userConfig *config.UserConfig) i
 	return 0
 }
 
+// resolveHeaderStyle determines the header style with priority: CLI > config > default.
+// A preset given on the CLI replaces the whole style.header section of the config.
+func resolveHeaderStyle(cmd *cobra.Command, userConfig *config.UserConfig) (*sheets.HeaderStyle, error) {
+	// Check if CLI flag was explicitly set
+	if cmd.Flags().Changed("header-style") {
+		return sheets.HeaderStylePreset(headerStyleFlag)
+	}
+
+	// Check if config has a value
+	style, err := configHeaderStyle(userConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid style.header in config: %w", err)
+	}
+	return style, nil
+}
+
+// configHeaderStyle returns the header style of the config: its preset, overridden by
+// the other style.header settings. It returns nil when nothing is styled.
+func configHeaderStyle(userConfig *config.UserConfig) (*sheets.HeaderStyle, error) {
+	style := &sheets.HeaderStyle{}
+	if preset, ok := userConfig.GetHeaderPreset(); ok {
+		presetStyle, err := sheets.HeaderStylePreset(preset)
+		if err != nil {
+			return nil, err
+		}
+		if presetStyle != nil {
+			style = presetStyle
+		}
+	}
+	if bold, ok := userConfig.GetHeaderBold(); ok {
+		style.Bold = bold
+	}
+	if background, ok := userConfig.GetHeaderBackground(); ok {
+		style.Background = background
+	}
+	if foreground, ok := userConfig.GetHeaderForeground(); ok {
+		style.Foreground = foreground
+	}
+	if border, ok := userConfig.GetHeaderBorder(); ok {
+		style.Border = border
+	}
+	if align, ok := userConfig.GetHeaderAlign(); ok {
+		style.Align = align
+	}
+
+	if err := style.Validate(); err != nil {
+		return nil, err
+	}
+	if *style == (sheets.HeaderStyle{}) {
+		return nil, nil
+	}
+	return style, nil
+}
+
+// resolveAutoResize determines whether columns are auto-resized with priority: CLI > config > default
+func resolveAutoResize(cmd *cobra.Command, userConfig *config.UserConfig) bool {
+	// Check if CLI flag was explicitly set
+	if cmd.Flags().Changed("auto-resize") {
+		return autoResizeFlag
+	}
+
+	// Check if config has a value
+	if autoResize, ok := userConfig.GetFormatAutoResize(); ok {
+		return autoResize
+	}
+
+	// Return default value
+	return false
+}
+
+// resolveMaxColWidth determines the maximum column width with priority: CLI > config > default
+func resolveMaxColWidth(cmd *cobra.Command, userConfig *config.UserConfig) int {
+	// Check if CLI flag was explicitly set
+	if cmd.Flags().Changed("max-col-width") {
+		return maxColWidthFlag
+	}
+
+	// Check if config has a value
+	if width, ok := userConfig.GetFormatMaxColWidth(); ok {
+		return width
+	}
+
+	// Return default value (0 means no limit)
+	return 0
+}
+
+// resolveDelimiter determines the input delimiter with priority: CLI > config > default.
+// It returns 0 when not set, which means the delimiter is detected from the input.
+func resolveDelimiter(cmd *cobra.Command, userConfig *config.UserConfig) (rune, error) {
+	// Check if CLI flags were explicitly set
+	if tsvFlag {
+		if cmd.Flags().Changed("delimiter") {
+			return 0, fmt.Errorf("--tsv cannot be used with --delimiter")
+		}
+		return '\t', nil
+	}
+	if cmd.Flags().Changed("delimiter") {
+		return parseDelimiter(delimiterFlag)
+	}
+
+	// Check if config has a value
+	if delimiter, ok := userConfig.GetInputDelimiter(); ok {
+		r, err := parseDelimiter(delimiter)
+		if err != nil {
+			return 0, fmt.Errorf("invalid input.delimiter in config: %w", err)
+		}
+		return r, nil
+	}
+
+	// Return default value (detected from the input)
+	return 0, nil
+}
+
+// resolveEncodingName determines the input encoding with priority: CLI > config > default
+func resolveEncodingName(cmd *cobra.Command, userConfig *config.UserConfig) (string, error) {
+	// Check if CLI flag was explicitly set
+	if cmd.Flags().Changed("encoding") {
+		if err := validateEncoding(encodingFlag); err != nil {
+			return "", err
+		}
+		return encodingFlag, nil
+	}
+
+	// Check if config has a value
+	if encodingName, ok := userConfig.GetInputEncoding(); ok {
+		if err := validateEncoding(encodingName); err != nil {
+			return "", fmt.Errorf("invalid input.encoding in config: %w", err)
+		}
+		return encodingName, nil
+	}
+
+	// Return default value (detected from the input)
+	return encodingAuto, nil
+}
+
 // initViper reads in config file and ENV variables if set.
 func initViper() {
 	if cfgFile != "" {
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// executeRoot runs the root command with args and returns its error. Flags set by
+// args are reset afterwards.
+func executeRoot(t *testing.T, args ...string) error {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				f.Value.Set(f.DefValue)
+				f.Changed = false
+			}
+		})
+		rootCmd.SetArgs(nil)
+	})
+
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	return rootCmd.Execute()
+}
+
+func TestStrictCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1\" x,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"default", []string{path}, "line 2"},
+		{"strict", []string{"--strict", path}, "line 2"},
+		{"strict with lenient", []string{"--strict", "--lenient", path}, "--lenient cannot be used with --strict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeRoot(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("gs-write %s error = %v, want an error with %q", strings.Join(tt.args, " "), err, tt.wantErr)
+			}
+		})
+	}
+}
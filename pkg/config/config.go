@@ -19,6 +19,9 @@ const (
 type UserConfig struct {
 	Freeze FreezeConfig `toml:"freeze"`
 	Filter FilterConfig `toml:"filter"`
+	Input  InputConfig  `toml:"input"`
+	Format FormatConfig `toml:"format"`
+	Style  StyleConfig  `toml:"style"`
 }
 
 // FreezeConfig represents freeze panes configuration
@@ -32,6 +35,34 @@ type FilterConfig struct {
 	HeaderRow *int `toml:"header_row,omitempty"`
 }
 
+// InputConfig represents input parsing configuration
+type InputConfig struct {
+	Delimiter *string `toml:"delimiter,omitempty"`
+	Encoding  *string `toml:"encoding,omitempty"`
+}
+
+// FormatConfig represents column format configuration
+type FormatConfig struct {
+	AutoResize  *bool `toml:"auto_resize,omitempty"`
+	MaxColWidth *int  `toml:"max_col_width,omitempty"`
+}
+
+// StyleConfig represents cell style configuration
+type StyleConfig struct {
+	Header HeaderStyleConfig `toml:"header"`
+}
+
+// HeaderStyleConfig represents header row style configuration. The fields that are
+// set override the preset.
+type HeaderStyleConfig struct {
+	Preset     *string `toml:"preset,omitempty"`
+	Bold       *bool   `toml:"bold,omitempty"`
+	Background *string `toml:"background,omitempty"`
+	Foreground *string `toml:"foreground,omitempty"`
+	Border     *string `toml:"border,omitempty"`
+	Align      *string `toml:"align,omitempty"`
+}
+
 // GetConfigPath returns the full path to the config file
 func GetConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
@@ -144,3 +175,183 @@ func (c *UserConfig) SetFilterHeaderRow(row int) {
 func (c *UserConfig) UnsetFilterHeaderRow() {
 	c.Filter.HeaderRow = nil
 }
+
+// GetInputDelimiter returns the input delimiter setting from config
+func (c *UserConfig) GetInputDelimiter() (string, bool) {
+	if c.Input.Delimiter != nil {
+		return *c.Input.Delimiter, true
+	}
+	return "", false
+}
+
+// SetInputDelimiter sets the input delimiter setting
+func (c *UserConfig) SetInputDelimiter(delimiter string) {
+	c.Input.Delimiter = &delimiter
+}
+
+// UnsetInputDelimiter removes the input delimiter setting
+func (c *UserConfig) UnsetInputDelimiter() {
+	c.Input.Delimiter = nil
+}
+
+// GetInputEncoding returns the input encoding setting from config
+func (c *UserConfig) GetInputEncoding() (string, bool) {
+	if c.Input.Encoding != nil {
+		return *c.Input.Encoding, true
+	}
+	return "", false
+}
+
+// SetInputEncoding sets the input encoding setting
+func (c *UserConfig) SetInputEncoding(encoding string) {
+	c.Input.Encoding = &encoding
+}
+
+// UnsetInputEncoding removes the input encoding setting
+func (c *UserConfig) UnsetInputEncoding() {
+	c.Input.Encoding = nil
+}
+
+// GetFormatAutoResize returns the auto-resize setting from config
+func (c *UserConfig) GetFormatAutoResize() (bool, bool) {
+	if c.Format.AutoResize != nil {
+		return *c.Format.AutoResize, true
+	}
+	return false, false
+}
+
+// SetFormatAutoResize sets the auto-resize setting
+func (c *UserConfig) SetFormatAutoResize(autoResize bool) {
+	c.Format.AutoResize = &autoResize
+}
+
+// UnsetFormatAutoResize removes the auto-resize setting
+func (c *UserConfig) UnsetFormatAutoResize() {
+	c.Format.AutoResize = nil
+}
+
+// GetFormatMaxColWidth returns the maximum column width setting from config
+func (c *UserConfig) GetFormatMaxColWidth() (int, bool) {
+	if c.Format.MaxColWidth != nil {
+		return *c.Format.MaxColWidth, true
+	}
+	return 0, false
+}
+
+// SetFormatMaxColWidth sets the maximum column width setting
+func (c *UserConfig) SetFormatMaxColWidth(width int) {
+	c.Format.MaxColWidth = &width
+}
+
+// UnsetFormatMaxColWidth removes the maximum column width setting
+func (c *UserConfig) UnsetFormatMaxColWidth() {
+	c.Format.MaxColWidth = nil
+}
+
+// GetHeaderPreset returns the header style preset setting from config
+func (c *UserConfig) GetHeaderPreset() (string, bool) {
+	if c.Style.Header.Preset != nil {
+		return *c.Style.Header.Preset, true
+	}
+	return "", false
+}
+
+// SetHeaderPreset sets the header style preset setting
+func (c *UserConfig) SetHeaderPreset(preset string) {
+	c.Style.Header.Preset = &preset
+}
+
+// UnsetHeaderPreset removes the header style preset setting
+func (c *UserConfig) UnsetHeaderPreset() {
+	c.Style.Header.Preset = nil
+}
+
+// GetHeaderBold returns the header style bold setting from config
+func (c *UserConfig) GetHeaderBold() (bool, bool) {
+	if c.Style.Header.Bold != nil {
+		return *c.Style.Header.Bold, true
+	}
+	return false, false
+}
+
+// SetHeaderBold sets the header style bold setting
+func (c *UserConfig) SetHeaderBold(bold bool) {
+	c.Style.Header.Bold = &bold
+}
+
+// UnsetHeaderBold removes the header style bold setting
+func (c *UserConfig) UnsetHeaderBold() {
+	c.Style.Header.Bold = nil
+}
+
+// GetHeaderBackground returns the header style background color setting from config
+func (c *UserConfig) GetHeaderBackground() (string, bool) {
+	if c.Style.Header.Background != nil {
+		return *c.Style.Header.Background, true
+	}
+	return "", false
+}
+
+// SetHeaderBackground sets the header style background color setting
+func (c *UserConfig) SetHeaderBackground(color string) {
+	c.Style.Header.Background = &color
+}
+
+// UnsetHeaderBackground removes the header style background color setting
+func (c *UserConfig) UnsetHeaderBackground() {
+	c.Style.Header.Background = nil
+}
+
+// GetHeaderForeground returns the header style foreground color setting from config
+func (c *UserConfig) GetHeaderForeground() (string, bool) {
+	if c.Style.Header.Foreground != nil {
+		return *c.Style.Header.Foreground, true
+	}
+	return "", false
+}
+
+// SetHeaderForeground sets the header style foreground color setting
+func (c *UserConfig) SetHeaderForeground(color string) {
+	c.Style.Header.Foreground = &color
+}
+
+// UnsetHeaderForeground removes the header style foreground color setting
+func (c *UserConfig) UnsetHeaderForeground() {
+	c.Style.Header.Foreground = nil
+}
+
+// GetHeaderBorder returns the header style border setting from config
+func (c *UserConfig) GetHeaderBorder() (string, bool) {
+	if c.Style.Header.Border != nil {
+		return *c.Style.Header.Border, true
+	}
+	return "", false
+}
+
+// SetHeaderBorder sets the header style border setting
+func (c *UserConfig) SetHeaderBorder(border string) {
+	c.Style.Header.Border = &border
+}
+
+// UnsetHeaderBorder removes the header style border setting
+func (c *UserConfig) UnsetHeaderBorder() {
+	c.Style.Header.Border = nil
+}
+
+// GetHeaderAlign returns the header style alignment setting from config
+func (c *UserConfig) GetHeaderAlign() (string, bool) {
+	if c.Style.Header.Align != nil {
+		return *c.Style.Header.Align, true
+	}
+	return "", false
+}
+
+// SetHeaderAlign sets the header style alignment setting
+func (c *UserConfig) SetHeaderAlign(align string) {
+	c.Style.Header.Align = &align
+}
+
+// UnsetHeaderAlign removes the header style alignment setting
+func (c *UserConfig) UnsetHeaderAlign() {
+	c.Style.Header.Align = nil
+}
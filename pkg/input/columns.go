@@ -0,0 +1,242 @@
+package input
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// tabWidth is the tab stop width used when expanding tabs in aligned output
+const tabWidth = 8
+
+// ReadColumns reads command output such as `ls -l` and splits each line into fields on
+// runs of whitespace. If maxFields is greater than 0, a line is split into at most
+// maxFields fields and the last field keeps the rest of the line, including its spaces
+// (like the COMMAND column of `ps aux`). Empty lines are skipped.
+func ReadColumns(r io.Reader, maxFields int) ([][]string, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	for _, line := range lines {
+		records = append(records, splitWhitespace(line, maxFields))
+	}
+	return records, nil
+}
+
+// ReadAlignedColumns reads column-aligned command output and splits every line at the
+// column positions of the words in the first (header) line, so that values containing
+// spaces stay in one cell. A value that crosses a column boundary (e.g. a right-aligned
+// number wider than its header) is assigned to the column holding most of it.
+// Header words separated by a single space (e.g. "Mounted on") form one column unless
+// the data shows a separate column there. Fields missing from short lines are empty.
+// If maxFields is greater than 0, columns beyond maxFields are merged into the last one.
+func ReadAlignedColumns(r io.Reader, maxFields int) ([][]string, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+
+	// Column boundaries are the start positions of the header columns
+	data := make([][]rune, len(lines)-1)
+	for i, line := range lines[1:] {
+		data[i] = []rune(line)
+	}
+	var starts []int
+	for _, span := range headerSpans([]rune(lines[0]), data) {
+		starts = append(starts, span[0])
+	}
+	if maxFields > 0 && len(starts) > maxFields {
+		starts = starts[:maxFields]
+	}
+	if len(starts) > 0 {
+		// The first column always starts at the beginning of the line
+		starts[0] = 0
+	}
+
+	var records [][]string
+	for _, line := range lines {
+		records = append(records, splitAligned([]rune(line), starts))
+	}
+	return records, nil
+}
+
+// headerSpans returns the [start, end) rune positions of the columns of the header line.
+// Words separated by a single space are one column when a data value crosses the start
+// of the second word, or when no data line has anything under it.
+func headerSpans(header []rune, data [][]rune) [][2]int {
+	words := wordSpans(header)
+	var spans [][2]int
+	for i, word := range words {
+		if i > 0 && word[0]-words[i-1][1] == 1 {
+			next := -1
+			if i+1 < len(words) {
+				next = words[i+1][0]
+			}
+			if !separateColumn(data, word[0], next) {
+				spans[len(spans)-1][1] = word[1]
+				continue
+			}
+		}
+		spans = append(spans, word)
+	}
+	return spans
+}
+
+// separateColumn reports whether the data has a column starting at start: no value
+// crosses start and some line has a value between start and next (the end of the
+// line if next < 0)
+func separateColumn(data [][]rune, start, next int) bool {
+	found := false
+	for _, line := range data {
+		if start < len(line) && !unicode.IsSpace(line[start]) && !unicode.IsSpace(line[start-1]) {
+			return false
+		}
+		end := len(line)
+		if next >= 0 {
+			end = min(next, len(line))
+		}
+		for pos := start; pos < end && !found; pos++ {
+			found = !unicode.IsSpace(line[pos])
+		}
+	}
+	return found
+}
+
+// readLines reads all non-empty lines of r without trailing whitespace
+func readLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRightFunc(scanner.Text(), unicode.IsSpace)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitWhitespace splits line on runs of whitespace into at most maxFields fields
+// (unlimited if maxFields <= 0), keeping the rest of the line in the last field
+func splitWhitespace(line string, maxFields int) []string {
+	runes := []rune(line)
+	spans := wordSpans(runes)
+
+	var fields []string
+	for i, span := range spans {
+		if maxFields > 0 && len(fields) == maxFields-1 && i < len(spans)-1 {
+			fields = append(fields, string(runes[span[0]:spans[len(spans)-1][1]]))
+			break
+		}
+		fields = append(fields, string(runes[span[0]:span[1]]))
+	}
+	return fields
+}
+
+// splitAligned splits line at the given column start positions, moving each boundary
+// that falls inside a word to the word edge so that the word stays in the column
+// holding most of it
+func splitAligned(line []rune, starts []int) []string {
+	if len(starts) == 0 {
+		return []string{strings.TrimSpace(string(line))}
+	}
+
+	// Boundaries past the end of a short line leave the remaining fields empty
+	bounds := make([]int, len(starts)+1)
+	for i, start := range starts {
+		bounds[i] = min(adjustBoundary(line, start), len(line))
+	}
+	bounds[len(starts)] = len(line)
+
+	fields := make([]string, len(starts))
+	for i := range starts {
+		from, to := bounds[i], bounds[i+1]
+		if i > 0 && from < bounds[i-1] {
+			from = bounds[i-1]
+		}
+		if to < from {
+			to = from
+		}
+		fields[i] = strings.TrimSpace(string(line[from:to]))
+	}
+	return fields
+}
+
+// adjustBoundary moves the boundary pos to the nearest edge of the word it splits,
+// keeping the word on the side holding most of it
+func adjustBoundary(line []rune, pos int) int {
+	if pos <= 0 || pos >= len(line) || unicode.IsSpace(line[pos]) || unicode.IsSpace(line[pos-1]) {
+		return pos
+	}
+
+	start := pos
+	for start > 0 && !unicode.IsSpace(line[start-1]) {
+		start--
+	}
+	end := pos
+	for end < len(line) && !unicode.IsSpace(line[end]) {
+		end++
+	}
+
+	if end-pos >= pos-start {
+		return start
+	}
+	return end
+}
+
+// wordSpans returns the [start, end) rune positions of the runs of non-whitespace
+func wordSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, [2]int{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
+
+// expandTabs replaces tabs with spaces up to the next tab stop
+func expandTabs(line string) string {
+	if !strings.ContainsRune(line, '\t') {
+		return line
+	}
+
+	var b strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col++
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxFields int
+		want      [][]string
+	}{
+		{
+			name:  "runs of whitespace",
+			input: "a  b\tc\n\n  d e  \n",
+			want:  [][]string{{"a", "b", "c"}, {"d", "e"}},
+		},
+		{
+			name:      "max fields keeps the rest of the line",
+			input:     "USER PID COMMAND\nroot 1 /sbin/init  splash --x\n",
+			maxFields: 3,
+			want:      [][]string{{"USER", "PID", "COMMAND"}, {"root", "1", "/sbin/init  splash --x"}},
+		},
+		{
+			name:      "short lines with max fields",
+			input:     "a b c d\nx\n",
+			maxFields: 3,
+			want:      [][]string{{"a", "b", "c d"}, {"x"}},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadColumns(strings.NewReader(tt.input), tt.maxFields)
+			if err != nil {
+				t.Fatalf("ReadColumns() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadColumns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAlignedColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxFields int
+		want      [][]string
+	}{
+		{
+			name:  "values with spaces",
+			input: "NAME        STATUS\nmy pod      Running ok\n",
+			want:  [][]string{{"NAME", "STATUS"}, {"my pod", "Running ok"}},
+		},
+		{
+			name:  "short row",
+			input: "A   B   C\nx   y\n",
+			want:  [][]string{{"A", "B", "C"}, {"x", "y", ""}},
+		},
+		{
+			name:  "row shorter than the last column start",
+			input: "NAME                                          VALUE\nshort\n",
+			want:  [][]string{{"NAME", "VALUE"}, {"short", ""}},
+		},
+		{
+			name: "df with multi-word header",
+			input: "Filesystem      Size  Used Avail Use% Mounted on\n" +
+				"/dev/sda1        50G   20G   28G  42% /\n" +
+				"/dev/sdb1       200G  150G   40G  79% /mnt/data disk\n" +
+				"tmpfs           1.0G     0  1.0G   0% /run\n",
+			want: [][]string{
+				{"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on"},
+				{"/dev/sda1", "50G", "20G", "28G", "42%", "/"},
+				{"/dev/sdb1", "200G", "150G", "40G", "79%", "/mnt/data disk"},
+				{"tmpfs", "1.0G", "0", "1.0G", "0%", "/run"},
+			},
+		},
+		{
+			name:  "multi-word header with short values",
+			input: "Use% Mounted on\n 42% /\n",
+			want:  [][]string{{"Use%", "Mounted on"}, {"42%", "/"}},
+		},
+		{
+			name:  "single-space header with separate columns",
+			input: "    PID TTY          TIME CMD\n  12345 pts/0    00:00:01 bash\n      1 ?        00:00:09 systemd\n",
+			want: [][]string{
+				{"PID", "TTY", "TIME", "CMD"},
+				{"12345", "pts/0", "00:00:01", "bash"},
+				{"1", "?", "00:00:09", "systemd"},
+			},
+		},
+		{
+			name:      "max fields",
+			input:     "A   B   C\n1   2   3 4\n",
+			maxFields: 2,
+			want:      [][]string{{"A", "B   C"}, {"1", "2   3 4"}},
+		},
+		{
+			name:  "tabs are expanded",
+			input: "A\tB\nx\ty\n",
+			want:  [][]string{{"A", "B"}, {"x", "y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadAlignedColumns(strings.NewReader(tt.input), tt.maxFields)
+			if err != nil {
+				t.Fatalf("ReadAlignedColumns() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadAlignedColumns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SniffSize is the number of bytes inspected by SniffDialect
+const SniffSize = 8 * 1024
+
+// delimiterCandidates are the delimiters SniffDialect chooses from, in order of preference
+var delimiterCandidates = []rune{',', '\t', ';', '|'}
+
+// Dialect describes how delimited text is formatted
+type Dialect struct {
+	// Delimiter is the field delimiter
+	Delimiter rune
+	// Quoted reports whether some fields are enclosed in double quotes
+	Quoted bool
+	// LazyQuotes reports whether stray double quotes appear in unquoted fields
+	LazyQuotes bool
+}
+
+// String returns a human-readable description of the dialect
+func (d Dialect) String() string {
+	quoting := "none"
+	switch {
+	case d.LazyQuotes:
+		quoting = "lazy (stray quotes in fields)"
+	case d.Quoted:
+		quoting = "double quotes"
+	}
+	return fmt.Sprintf("delimiter=%s, quoting=%s", DelimiterName(d.Delimiter), quoting)
+}
+
+// DelimiterName returns a readable name for the delimiter
+func DelimiterName(delimiter rune) string {
+	switch delimiter {
+	case ',':
+		return "comma"
+	case '\t':
+		return "tab"
+	case ';':
+		return "semicolon"
+	case '|':
+		return "pipe"
+	case ' ':
+		return "space"
+	default:
+		return fmt.Sprintf("%q", delimiter)
+	}
+}
+
+// SniffDialect guesses the delimiter and quoting style from a sample of the input.
+// The delimiter that splits the sample into the most consistent number of fields
+// (more than one) wins; if none does, comma is assumed.
+func SniffDialect(sample []byte) Dialect {
+	// Drop the trailing partial line of a truncated sample
+	truncated := len(sample) >= SniffSize
+	if truncated {
+		if i := bytes.LastIndexByte(sample, '\n'); i > 0 {
+			sample = sample[:i+1]
+		}
+	}
+
+	best := Dialect{Delimiter: ','}
+	bestScore := 0.0
+	for _, delimiter := range delimiterCandidates {
+		score := delimiterScore(sample, delimiter)
+		if score > bestScore {
+			best.Delimiter = delimiter
+			bestScore = score
+		}
+	}
+
+	best.Quoted, best.LazyQuotes = sniffQuoting(sample, best.Delimiter, truncated)
+	return best
+}
+
+// delimiterScore rates how well the delimiter splits the sample into records with
+// the same number of fields. Records with a single field score nothing.
+func delimiterScore(sample []byte, delimiter rune) float64 {
+	reader := csv.NewReader(bytes.NewReader(sample))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	counts := make(map[int]int)
+	records := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Score the records read so far (the sample may end inside a quoted field)
+			break
+		}
+		counts[len(record)]++
+		records++
+	}
+	if records == 0 {
+		return 0
+	}
+
+	// The most common field count and how many records have it
+	mode, modeCount := 0, 0
+	for fields, n := range counts {
+		if n > modeCount || (n == modeCount && fields > mode) {
+			mode, modeCount = fields, n
+		}
+	}
+	if mode <= 1 {
+		return 0
+	}
+
+	// Consistency dominates; more fields break ties between equally consistent candidates
+	consistency := float64(modeCount) / float64(records)
+	return consistency*100 + float64(mode)/1000
+}
+
+// sniffQuoting reports whether fields are double-quoted and whether stray quotes
+// require lazy quote handling. In a truncated sample, an unterminated quoted field
+// may just be cut off, so only bare quotes count.
+func sniffQuoting(sample []byte, delimiter rune, truncated bool) (quoted, lazy bool) {
+	reader := csv.NewReader(bytes.NewReader(sample))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	for {
+		_, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				lazy = errors.Is(parseErr.Err, csv.ErrBareQuote) || (!truncated && errors.Is(parseErr.Err, csv.ErrQuote))
+			}
+			break
+		}
+	}
+
+	quoted = bytes.HasPrefix(sample, []byte{'"'}) ||
+		bytes.Contains(sample, []byte("\n\"")) ||
+		bytes.Contains(sample, []byte(string(delimiter)+"\""))
+	return quoted, lazy
+}
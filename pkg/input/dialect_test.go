@@ -0,0 +1,47 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniffDialect(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		want   Dialect
+	}{
+		{"comma", "a,b,c\n1,2,3\n", Dialect{Delimiter: ','}},
+		{"tab", "a\tb\n1\t2,5\n", Dialect{Delimiter: '\t'}},
+		{"semicolon with decimal commas", "a;b\n1,5;2,5\n3,0;4\n", Dialect{Delimiter: ';'}},
+		{"pipe", "a|b|c\n1|2|3\n", Dialect{Delimiter: '|'}},
+		{"quoted fields", "\"a\",\"b\"\n\"1,2\",3\n", Dialect{Delimiter: ',', Quoted: true}},
+		{"stray quotes", "name,size\n12\" pipe,3\n", Dialect{Delimiter: ',', LazyQuotes: true}},
+		{"single column falls back to comma", "name\nalice\n", Dialect{Delimiter: ','}},
+		{"empty", "", Dialect{Delimiter: ','}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SniffDialect([]byte(tt.sample)); got != tt.want {
+				t.Errorf("SniffDialect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniffDialectTruncated(t *testing.T) {
+	// A sample cut inside a quoted field that spans lines is not lazy
+	var b strings.Builder
+	b.WriteString("a,b\n")
+	for b.Len() < SniffSize-10 {
+		b.WriteString("\"x\",1\n")
+	}
+	b.WriteString("\"multi-line\nfield")
+	b.WriteString(strings.Repeat("y", SniffSize))
+
+	want := Dialect{Delimiter: ',', Quoted: true}
+	if got := SniffDialect([]byte(b.String())); got != want {
+		t.Errorf("SniffDialect() = %v, want %v", got, want)
+	}
+}
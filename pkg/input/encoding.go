@@ -0,0 +1,167 @@
+package input
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// EncodingSniffSize is the number of bytes inspected by DetectEncoding
+const EncodingSniffSize = 64 * 1024
+
+// Encoding names returned by DetectEncoding
+const (
+	EncodingUTF8      = "utf-8"
+	EncodingUTF16LE   = "utf-16le"
+	EncodingUTF16BE   = "utf-16be"
+	EncodingShiftJIS  = "sjis"
+	EncodingEUCJP     = "euc-jp"
+	EncodingISO2022JP = "iso-2022-jp"
+)
+
+// Byte order marks
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// iso2022JPEscapes are the escape sequences that switch character sets in ISO-2022-JP
+var iso2022JPEscapes = [][]byte{
+	[]byte("\x1b$B"), []byte("\x1b$@"), []byte("\x1b(J"), []byte("\x1b(B"),
+}
+
+// UTF8BOMLength returns the length of the UTF-8 byte order mark at the beginning of data (0 if none)
+func UTF8BOMLength(data []byte) int {
+	if bytes.HasPrefix(data, bomUTF8) {
+		return len(bomUTF8)
+	}
+	return 0
+}
+
+// DetectEncoding guesses the character encoding of a sample from the beginning of the
+// input: a byte order mark wins, then ISO-2022-JP escape sequences and valid UTF-8;
+// otherwise Shift_JIS and EUC-JP are told apart by which one decodes the sample with
+// fewer invalid sequences. truncated reports whether the input continues after the sample.
+func DetectEncoding(sample []byte, truncated bool) string {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	if isASCII(sample) {
+		for _, esc := range iso2022JPEscapes {
+			if bytes.Contains(sample, esc) {
+				return EncodingISO2022JP
+			}
+		}
+		return EncodingUTF8
+	}
+
+	// A truncated sample may end in the middle of a character
+	if truncated {
+		sample = trimIncompleteRune(sample)
+	}
+	if utf8.Valid(sample) {
+		return EncodingUTF8
+	}
+
+	sjisErrors, halfWidthKana := scanShiftJIS(sample)
+	eucErrors := scanEUCJP(sample)
+	switch {
+	case sjisErrors < eucErrors:
+		return EncodingShiftJIS
+	case eucErrors < sjisErrors:
+		return EncodingEUCJP
+	case halfWidthKana > 0:
+		// EUC-JP read as Shift_JIS looks like half-width katakana, which is rare in real text
+		return EncodingEUCJP
+	default:
+		return EncodingShiftJIS
+	}
+}
+
+// isASCII reports whether data contains only 7-bit bytes
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// trimIncompleteRune removes an incomplete UTF-8 sequence at the end of data
+func trimIncompleteRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
+
+// scanShiftJIS counts the invalid sequences and half-width katakana when data is read as Shift_JIS
+func scanShiftJIS(data []byte) (errors, halfWidthKana int) {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+		case b >= 0xA1 && b <= 0xDF:
+			halfWidthKana++
+		case (b >= 0x81 && b <= 0x9F) || (b >= 0xE0 && b <= 0xFC):
+			if i+1 >= len(data) {
+				break
+			}
+			t := data[i+1]
+			if (t >= 0x40 && t <= 0x7E) || (t >= 0x80 && t <= 0xFC) {
+				i++
+			} else {
+				errors++
+			}
+		default:
+			errors++
+		}
+	}
+	return errors, halfWidthKana
+}
+
+// scanEUCJP counts the invalid sequences when data is read as EUC-JP
+func scanEUCJP(data []byte) (errors int) {
+	isEUCByte := func(b byte) bool { return b >= 0xA1 && b <= 0xFE }
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b < 0x80:
+		case b == 0x8E:
+			// Half-width katakana
+			if i+1 < len(data) && data[i+1] >= 0xA1 && data[i+1] <= 0xDF {
+				i++
+			} else if i+1 < len(data) {
+				errors++
+			}
+		case b == 0x8F:
+			// JIS X 0212
+			if i+2 < len(data) && isEUCByte(data[i+1]) && isEUCByte(data[i+2]) {
+				i += 2
+			} else if i+2 < len(data) {
+				errors++
+			}
+		case isEUCByte(b):
+			if i+1 < len(data) && isEUCByte(data[i+1]) {
+				i++
+			} else if i+1 < len(data) {
+				errors++
+			}
+		default:
+			errors++
+		}
+	}
+	return errors
+}
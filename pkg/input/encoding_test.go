@@ -0,0 +1,56 @@
+package input
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	const text = "名前,住所,電話番号\n山田太郎,東京都千代田区,03-1234-5678\nｶﾀｶﾅ,大阪府,06-0000-0000\n"
+	encode := func(enc encoding.Encoding, s string) []byte {
+		b, err := enc.NewEncoder().Bytes([]byte(s))
+		if err != nil {
+			t.Fatalf("failed to encode test data: %v", err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name      string
+		sample    []byte
+		truncated bool
+		want      string
+	}{
+		{"ascii", []byte("a,b\n1,2\n"), false, EncodingUTF8},
+		{"utf-8", []byte(text), false, EncodingUTF8},
+		{"utf-8 with BOM", append([]byte("\xef\xbb\xbf"), "a,b\n"...), false, EncodingUTF8},
+		{"utf-16le BOM", []byte("\xff\xfea\x00,\x00"), false, EncodingUTF16LE},
+		{"utf-16be BOM", []byte("\xfe\xff\x00a\x00,"), false, EncodingUTF16BE},
+		{"shift_jis", encode(japanese.ShiftJIS, text), false, EncodingShiftJIS},
+		{"shift_jis without half-width kana", encode(japanese.ShiftJIS, "名前,住所\n山田,東京\n"), false, EncodingShiftJIS},
+		{"euc-jp", encode(japanese.EUCJP, text), false, EncodingEUCJP},
+		{"euc-jp without half-width kana", encode(japanese.EUCJP, "名前,住所\n山田,東京\n"), false, EncodingEUCJP},
+		{"iso-2022-jp", encode(japanese.ISO2022JP, text), false, EncodingISO2022JP},
+		{"utf-8 cut in the middle of a character", []byte(text)[:len("名前,住")+1], true, EncodingUTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.sample, tt.truncated); got != tt.want {
+				t.Errorf("DetectEncoding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUTF8BOMLength(t *testing.T) {
+	if n := UTF8BOMLength([]byte("\xef\xbb\xbfa")); n != 3 {
+		t.Errorf("UTF8BOMLength() = %d, want 3", n)
+	}
+	if n := UTF8BOMLength(bytes.Repeat([]byte("a"), 3)); n != 0 {
+		t.Errorf("UTF8BOMLength() = %d, want 0", n)
+	}
+}
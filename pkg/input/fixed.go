@@ -0,0 +1,194 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Units of fixed-width column widths
+const (
+	// WidthBytes measures widths in bytes of the input before decoding
+	WidthBytes = "bytes"
+	// WidthChars measures widths in characters after decoding
+	WidthChars = "chars"
+)
+
+// FixedColumn is a column of a fixed-width layout
+type FixedColumn struct {
+	// Name is the column name written as the header (empty if the layout has no names)
+	Name string
+	// Width is the width of the column
+	Width int
+}
+
+// ParseWidths parses a comma-separated list of column widths such as "10,8,20"
+func ParseWidths(value string) ([]FixedColumn, error) {
+	var columns []FixedColumn
+	for _, part := range strings.Split(value, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("invalid width %q: widths must be positive integers", strings.TrimSpace(part))
+		}
+		columns = append(columns, FixedColumn{Width: width})
+	}
+	return columns, nil
+}
+
+// ReadFixedSpec reads a fixed-width layout with one column per line as "name width"
+// (separated by whitespace or a comma). Empty lines and lines starting with # are ignored.
+func ReadFixedSpec(r io.Reader) ([]FixedColumn, error) {
+	scanner := bufio.NewScanner(r)
+
+	var columns []FixedColumn
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sep := strings.LastIndexAny(line, ", \t")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected \"name width\" (got: %q)", lineNum, line)
+		}
+		name := strings.TrimSpace(strings.TrimRight(line[:sep], ", \t"))
+		width, err := strconv.Atoi(line[sep+1:])
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("line %d: invalid width %q", lineNum, line[sep+1:])
+		}
+		columns = append(columns, FixedColumn{Name: name, Width: width})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns defined")
+	}
+	return columns, nil
+}
+
+// ReadFixed reads fixed-width lines from r and splits each line into the given columns,
+// trimming the padding around each value. With WidthBytes, the widths are measured on
+// the raw bytes and the values of each line are then converted to UTF-8 with decoder
+// (nil means the input is already UTF-8). With WidthChars, r must already be UTF-8. Data beyond the
+// last column is ignored, and empty lines are skipped. If the columns have names, they
+// are written as the header row.
+func ReadFixed(r io.Reader, columns []FixedColumn, unit string, decoder *encoding.Decoder) ([][]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var records [][]string
+	if columns[0].Name != "" {
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			header[i] = c.Name
+		}
+		records = append(records, header)
+	}
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimRight(scanner.Bytes(), "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var record []string
+		var err error
+		if unit == WidthChars {
+			record = splitFixedChars(string(line), columns)
+		} else {
+			record, err = splitFixedBytes(line, columns, decoder)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// splitFixedBytes splits a raw line at byte widths and decodes the values. The values
+// of a line go through one decoder in order, so stateful encodings such as ISO-2022-JP
+// keep their shift state across columns, and a character cut by a column boundary is
+// decoded as part of the next column.
+func splitFixedBytes(line []byte, columns []FixedColumn, decoder *encoding.Decoder) ([]string, error) {
+	if decoder != nil {
+		decoder.Reset()
+	}
+
+	record := make([]string, len(columns))
+	var pending []byte
+	pos := 0
+	for i, c := range columns {
+		if pos >= len(line) {
+			break
+		}
+		end := pos + c.Width
+		if end > len(line) {
+			end = len(line)
+		}
+		field := line[pos:end]
+		if decoder != nil {
+			atEOF := end == len(line) || i == len(columns)-1
+			decoded, rest, err := decodeFixedField(decoder, append(pending, field...), atEOF)
+			if err != nil {
+				return nil, err
+			}
+			field, pending = decoded, rest
+		}
+		record[i] = strings.TrimSpace(string(field))
+		pos = end
+	}
+	return record, nil
+}
+
+// decodeFixedField decodes src with decoder and returns the bytes of an incomplete
+// character at the end, which are left for the next column unless atEOF is true
+func decodeFixedField(decoder *encoding.Decoder, src []byte, atEOF bool) ([]byte, []byte, error) {
+	var decoded []byte
+	buf := make([]byte, 4*len(src)+utf8.UTFMax)
+	for {
+		nDst, nSrc, err := decoder.Transform(buf, src, atEOF)
+		decoded = append(decoded, buf[:nDst]...)
+		src = src[nSrc:]
+		switch {
+		case err == nil:
+			return decoded, nil, nil
+		case err == transform.ErrShortDst && nDst+nSrc > 0:
+			continue
+		case err == transform.ErrShortSrc && !atEOF:
+			return decoded, append([]byte(nil), src...), nil
+		default:
+			return nil, nil, err
+		}
+	}
+}
+
+// splitFixedChars splits a line at character widths
+func splitFixedChars(line string, columns []FixedColumn) []string {
+	runes := []rune(line)
+	record := make([]string, len(columns))
+	pos := 0
+	for i, c := range columns {
+		if pos >= len(runes) {
+			break
+		}
+		end := pos + c.Width
+		if end > len(runes) {
+			end = len(runes)
+		}
+		record[i] = strings.TrimSpace(string(runes[pos:end]))
+		pos = end
+	}
+	return record
+}
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestParseWidths(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []FixedColumn
+		wantErr bool
+	}{
+		{"widths", "10,8,20", []FixedColumn{{Width: 10}, {Width: 8}, {Width: 20}}, false},
+		{"spaces", " 3 , 4 ", []FixedColumn{{Width: 3}, {Width: 4}}, false},
+		{"zero", "3,0", nil, true},
+		{"negative", "-1", nil, true},
+		{"not a number", "3,x", nil, true},
+		{"empty width", "3,,4", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWidths(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWidths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWidths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFixedSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []FixedColumn
+		wantErr bool
+	}{
+		{
+			name: "names and widths",
+			spec: "# layout\nid 5\n\ncustomer name\t20\nprice, 8\n",
+			want: []FixedColumn{{Name: "id", Width: 5}, {Name: "customer name", Width: 20}, {Name: "price", Width: 8}},
+		},
+		{"missing width", "id\n", nil, true},
+		{"invalid width", "id 0\n", nil, true},
+		{"no columns", "# nothing\n\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFixedSpec(strings.NewReader(tt.spec))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadFixedSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadFixedSpec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFixed(t *testing.T) {
+	encode := func(enc encoding.Encoding, s string) string {
+		b, err := enc.NewEncoder().String(s)
+		if err != nil {
+			t.Fatalf("failed to encode test data: %v", err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		columns []FixedColumn
+		unit    string
+		enc     encoding.Encoding
+		want    [][]string
+	}{
+		{
+			name:    "names become the header",
+			input:   "a    1   \r\n\nbc   22  extra\nd\n",
+			columns: []FixedColumn{{Name: "name", Width: 5}, {Name: "qty", Width: 4}},
+			unit:    WidthBytes,
+			want:    [][]string{{"name", "qty"}, {"a", "1"}, {"bc", "22"}, {"d", ""}},
+		},
+		{
+			name:    "utf-8 characters",
+			input:   "山田  東京\n",
+			columns: []FixedColumn{{Width: 4}, {Width: 2}},
+			unit:    WidthChars,
+			want:    [][]string{{"山田", "東京"}},
+		},
+		{
+			name:    "shift_jis bytes",
+			input:   encode(japanese.ShiftJIS, "山田  東京都\n"),
+			columns: []FixedColumn{{Width: 6}, {Width: 4}},
+			unit:    WidthBytes,
+			enc:     japanese.ShiftJIS,
+			want:    [][]string{{"山田", "東京"}},
+		},
+		{
+			name:    "shift_jis character cut by a column boundary",
+			input:   encode(japanese.ShiftJIS, "山田\n"),
+			columns: []FixedColumn{{Width: 3}, {Width: 1}},
+			unit:    WidthBytes,
+			enc:     japanese.ShiftJIS,
+			want:    [][]string{{"山", "田"}},
+		},
+		{
+			name:    "iso-2022-jp shift state continues across columns",
+			input:   encode(japanese.ISO2022JP, "山田東京\n"),
+			columns: []FixedColumn{{Width: 7}, {Width: 7}},
+			unit:    WidthBytes,
+			enc:     japanese.ISO2022JP,
+			want:    [][]string{{"山田", "東京"}},
+		},
+		{
+			name:    "iso-2022-jp state is reset for each line",
+			input:   encode(japanese.ISO2022JP, "山田東京\n") + encode(japanese.ISO2022JP, "ab東京\n"),
+			columns: []FixedColumn{{Width: 7}, {Width: 7}},
+			unit:    WidthBytes,
+			enc:     japanese.ISO2022JP,
+			want:    [][]string{{"山田", "東京"}, {"ab東", "京"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoder *encoding.Decoder
+			if tt.enc != nil {
+				decoder = tt.enc.NewDecoder()
+			}
+			got, err := ReadFixed(strings.NewReader(tt.input), tt.columns, tt.unit, decoder)
+			if err != nil {
+				t.Fatalf("ReadFixed() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadFixed() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package input
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ReadHTMLTables extracts the <table> elements of an HTML document. Each table is
+// named after its <caption>. Cells spanning several columns or rows (colspan, rowspan)
+// are followed by empty cells so that the columns stay aligned, and nested tables are
+// extracted as separate tables.
+func ReadHTMLTables(r io.Reader) ([]Table, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Table {
+			if table := readHTMLTable(n); len(table.Rows) > 0 {
+				tables = append(tables, table)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return tables, nil
+}
+
+// readHTMLTable converts a <table> element into a table, ignoring nested tables
+func readHTMLTable(table *html.Node) Table {
+	var result Table
+
+	// pending holds the cells still covered by a rowspan, by column
+	pending := make(map[int]int)
+	for _, tr := range tableRows(table) {
+		var row []string
+		col := 0
+		fillSpanned := func() {
+			for pending[col] > 0 {
+				pending[col]--
+				row = append(row, "")
+				col++
+			}
+		}
+
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || (c.DataAtom != atom.Td && c.DataAtom != atom.Th) {
+				continue
+			}
+			fillSpanned()
+
+			colspan := spanAttr(c, "colspan")
+			rowspan := spanAttr(c, "rowspan")
+			row = append(row, cellText(c))
+			for i := 0; i < colspan; i++ {
+				if i > 0 {
+					row = append(row, "")
+				}
+				if rowspan > 1 {
+					pending[col] = rowspan - 1
+				}
+				col++
+			}
+		}
+		fillSpanned()
+		result.Rows = append(result.Rows, row)
+	}
+
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == atom.Caption {
+			result.Name = cellText(c)
+			break
+		}
+	}
+	return result
+}
+
+// tableRows returns the <tr> elements of a table, including those in <thead>,
+// <tbody> and <tfoot>, but not those of nested tables
+func tableRows(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Tr:
+			rows = append(rows, c)
+		case atom.Thead, atom.Tbody, atom.Tfoot:
+			for tr := c.FirstChild; tr != nil; tr = tr.NextSibling {
+				if tr.Type == html.ElementNode && tr.DataAtom == atom.Tr {
+					rows = append(rows, tr)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// spanAttr returns the value of a colspan or rowspan attribute (1 if missing or invalid)
+func spanAttr(n *html.Node, name string) int {
+	for _, attr := range n.Attr {
+		if attr.Key != name {
+			continue
+		}
+		span := 0
+		for _, r := range strings.TrimSpace(attr.Val) {
+			if r < '0' || r > '9' {
+				break
+			}
+			span = span*10 + int(r-'0')
+			if span > 1000 {
+				return 1000
+			}
+		}
+		if span < 1 {
+			return 1
+		}
+		return span
+	}
+	return 1
+}
+
+// cellText returns the text of an element with whitespace collapsed as a browser
+// renders it. <br> becomes a line break, and nested tables are skipped.
+func cellText(n *html.Node) string {
+	lines := []string{""}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			lines[len(lines)-1] += n.Data
+			return
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			lines = append(lines, "")
+			return
+		case n.Type == html.ElementNode && (n.DataAtom == atom.Table || n.DataAtom == atom.Script || n.DataAtom == atom.Style):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
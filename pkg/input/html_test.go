@@ -0,0 +1,78 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadHTMLTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Table
+	}{
+		{
+			name: "caption and sections",
+			input: `<table><caption> Sales </caption>
+				<thead><tr><th>Name</th><th>Qty</th></tr></thead>
+				<tbody><tr><td>a</td><td>1</td></tr></tbody>
+				<tfoot><tr><td>total</td><td>1</td></tr></tfoot></table>`,
+			want: []Table{{Name: "Sales", Rows: [][]string{{"Name", "Qty"}, {"a", "1"}, {"total", "1"}}}},
+		},
+		{
+			name:  "colspan is followed by empty cells",
+			input: `<table><tr><th colspan="2">Name</th><th>Qty</th></tr><tr><td>a</td><td>b</td><td>1</td></tr></table>`,
+			want:  []Table{{Rows: [][]string{{"Name", "", "Qty"}, {"a", "b", "1"}}}},
+		},
+		{
+			name: "rowspan leaves empty cells below",
+			input: `<table><tr><td rowspan="3">x</td><td>1</td></tr><tr><td>2</td></tr>` +
+				`<tr><td>3</td></tr><tr><td>y</td><td>4</td></tr></table>`,
+			want: []Table{{Rows: [][]string{{"x", "1"}, {"", "2"}, {"", "3"}, {"y", "4"}}}},
+		},
+		{
+			name:  "rowspan in the last column",
+			input: `<table><tr><td>a</td><td rowspan="2">x</td></tr><tr><td>b</td></tr></table>`,
+			want:  []Table{{Rows: [][]string{{"a", "x"}, {"b", ""}}}},
+		},
+		{
+			name:  "invalid spans count as one",
+			input: `<table><tr><td colspan="0">a</td><td colspan="x">b</td></tr></table>`,
+			want:  []Table{{Rows: [][]string{{"a", "b"}}}},
+		},
+		{
+			name: "text of nested tags",
+			input: `<table><tr><td><b>bold</b> and <a href="#">link</a></td>` +
+				`<td>line 1<br>line   2</td><td><script>x()</script>ok</td></tr></table>`,
+			want: []Table{{Rows: [][]string{{"bold and link", "line 1\nline 2", "ok"}}}},
+		},
+		{
+			name: "nested tables are extracted separately",
+			input: `<table><tr><td>outer<table><caption>inner</caption><tr><td>1</td></tr></table></td></tr></table>` +
+				`<table><tr><td>second</td></tr></table>`,
+			want: []Table{
+				{Rows: [][]string{{"outer"}}},
+				{Name: "inner", Rows: [][]string{{"1"}}},
+				{Rows: [][]string{{"second"}}},
+			},
+		},
+		{
+			name:  "empty tables are skipped",
+			input: `<p>no rows</p><table></table>`,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadHTMLTables(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadHTMLTables() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadHTMLTables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
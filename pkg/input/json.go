@@ -0,0 +1,264 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Modes for nested JSON arrays
+const (
+	// ArraysSerialize writes an array as its JSON text in a single cell
+	ArraysSerialize = "serialize"
+	// ArraysExplode writes one row per array element, repeating the other fields
+	ArraysExplode = "explode"
+)
+
+// valueColumn is the column name used for top-level values that are not objects
+const valueColumn = "value"
+
+// object is a JSON object that keeps its keys in document order
+type object []member
+
+// member is a key-value pair of a JSON object
+type member struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON encodes the object with its keys in document order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// field is a flattened cell of a row
+type field struct {
+	key   string
+	value string
+}
+
+// ReadJSON reads a JSON array of objects (or a single object) and converts each object
+// into a row. Nested objects are flattened with dotted keys, and arrays are serialized
+// or exploded according to arrays. The header is the union of keys in first-seen order.
+func ReadJSON(r io.Reader, arrays string) ([][]string, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	value, err := decodeValue(dec)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the top-level JSON value")
+	}
+
+	var rows [][]field
+	if elements, ok := value.([]interface{}); ok {
+		for _, element := range elements {
+			rows = append(rows, flatten("", element, arrays == ArraysExplode)...)
+		}
+	} else {
+		rows = flatten("", value, arrays == ArraysExplode)
+	}
+	return toRecords(rows), nil
+}
+
+// ReadNDJSON reads newline-delimited JSON (one object per line) and converts each
+// object into a row in the same way as ReadJSON
+func ReadNDJSON(r io.Reader, arrays string) ([][]string, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var rows [][]field
+	for record := 1; ; record++ {
+		value, err := decodeValue(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", record, err)
+		}
+		rows = append(rows, flatten("", value, arrays == ArraysExplode)...)
+	}
+	return toRecords(rows), nil
+}
+
+// decodeValue decodes the next JSON value, keeping object keys in document order
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var obj object
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, member{key: keyToken.(string), value: value})
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			elements := []interface{}{}
+			for dec.More() {
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return elements, nil
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSON", rune(t))
+		}
+	default:
+		return t, nil
+	}
+}
+
+// flatten converts a JSON value into one or more rows of dotted-key fields.
+// With explode, every element of an array produces its own row(s).
+func flatten(prefix string, value interface{}, explode bool) [][]field {
+	switch v := value.(type) {
+	case object:
+		if len(v) == 0 {
+			return [][]field{{{key: keyOrValue(prefix), value: ""}}}
+		}
+		rows := [][]field{{}}
+		for _, m := range v {
+			rows = crossJoin(rows, flatten(joinKey(prefix, m.key), m.value, explode))
+		}
+		return rows
+	case []interface{}:
+		if !explode || len(v) == 0 {
+			text := ""
+			if len(v) > 0 {
+				b, _ := json.Marshal(v)
+				text = string(b)
+			}
+			return [][]field{{{key: keyOrValue(prefix), value: text}}}
+		}
+		var rows [][]field
+		for _, element := range v {
+			rows = append(rows, flatten(prefix, element, explode)...)
+		}
+		return rows
+	default:
+		return [][]field{{{key: keyOrValue(prefix), value: scalarString(v)}}}
+	}
+}
+
+// crossJoin combines every row of a with every row of b
+func crossJoin(a, b [][]field) [][]field {
+	rows := make([][]field, 0, len(a)*len(b))
+	for _, x := range a {
+		for _, y := range b {
+			row := make([]field, 0, len(x)+len(y))
+			row = append(row, x...)
+			row = append(row, y...)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// toRecords converts rows of fields into records with the union of keys as the header
+func toRecords(rows [][]field) [][]string {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var header []string
+	for _, row := range rows {
+		for _, f := range row {
+			if _, ok := index[f.key]; !ok {
+				index[f.key] = len(header)
+				header = append(header, f.key)
+			}
+		}
+	}
+
+	records := [][]string{header}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for _, f := range row {
+			record[index[f.key]] = f.value
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// scalarString converts a JSON scalar into cell text (null becomes an empty cell)
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// joinKey joins a parent key and a child key with a dot
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// keyOrValue returns key, or the value column name for top-level non-object values
+func keyOrValue(key string) string {
+	if key == "" {
+		return valueColumn
+	}
+	return key
+}
@@ -0,0 +1,96 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		arrays string
+		want   [][]string
+	}{
+		{
+			name:   "nested objects and key order",
+			input:  `[{"b": 1, "a": {"y": true, "x": null}}, {"c": "3", "b": 2.50}]`,
+			arrays: ArraysSerialize,
+			want:   [][]string{{"b", "a.y", "a.x", "c"}, {"1", "true", "", ""}, {"2.50", "", "", "3"}},
+		},
+		{
+			name:   "single object",
+			input:  `{"id": 1}`,
+			arrays: ArraysSerialize,
+			want:   [][]string{{"id"}, {"1"}},
+		},
+		{
+			name:   "serialize arrays",
+			input:  `[{"id": 1, "tags": ["a", "b"], "empty": []}]`,
+			arrays: ArraysSerialize,
+			want:   [][]string{{"id", "tags", "empty"}, {"1", `["a","b"]`, ""}},
+		},
+		{
+			name:   "explode arrays",
+			input:  `[{"id": 1, "tags": ["a", "b"]}, {"id": 2, "tags": []}]`,
+			arrays: ArraysExplode,
+			want:   [][]string{{"id", "tags"}, {"1", "a"}, {"1", "b"}, {"2", ""}},
+		},
+		{
+			name:   "explode arrays of objects",
+			input:  `{"order": 7, "items": [{"sku": "A", "qty": 1}, {"sku": "B", "qty": 2}]}`,
+			arrays: ArraysExplode,
+			want:   [][]string{{"order", "items.sku", "items.qty"}, {"7", "A", "1"}, {"7", "B", "2"}},
+		},
+		{
+			name:   "explode several arrays",
+			input:  `{"x": [1, 2], "y": ["a", "b"]}`,
+			arrays: ArraysExplode,
+			want:   [][]string{{"x", "y"}, {"1", "a"}, {"1", "b"}, {"2", "a"}, {"2", "b"}},
+		},
+		{
+			name:   "top-level values",
+			input:  `[1, "two"]`,
+			arrays: ArraysSerialize,
+			want:   [][]string{{"value"}, {"1"}, {"two"}},
+		},
+		{
+			name:   "empty input",
+			input:  "",
+			arrays: ArraysSerialize,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadJSON(strings.NewReader(tt.input), tt.arrays)
+			if err != nil {
+				t.Fatalf("ReadJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJSONTrailingData(t *testing.T) {
+	if _, err := ReadJSON(strings.NewReader(`{"a": 1} {"a": 2}`), ArraysSerialize); err == nil {
+		t.Error("ReadJSON() error = nil, want an error for data after the top-level value")
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	input := "{\"id\": 1, \"tags\": [\"a\", \"b\"]}\n{\"id\": 2, \"name\": \"x\"}\n"
+	want := [][]string{{"id", "tags", "name"}, {"1", "a", ""}, {"1", "b", ""}, {"2", "", "x"}}
+
+	got, err := ReadNDJSON(strings.NewReader(input), ArraysExplode)
+	if err != nil {
+		t.Fatalf("ReadNDJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadNDJSON() = %q, want %q", got, want)
+	}
+}
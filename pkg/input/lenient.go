@@ -0,0 +1,141 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// LenientReport lists the lines repaired or skipped by ReadCSVLenient. Line numbers
+// are 1-indexed and refer to the line where the record starts.
+type LenientReport struct {
+	// StrayQuotes is the lines with quotes that are invalid in strict CSV, read as-is
+	StrayQuotes []int
+	// Padded is the lines with fewer fields than the header, padded with empty cells
+	Padded []int
+	// Long is the lines with more fields than the header, kept as they are
+	Long []int
+	// Skipped is the malformed lines that were left out
+	Skipped []int
+}
+
+// Empty reports whether no line was repaired or skipped
+func (r LenientReport) Empty() bool {
+	return len(r.StrayQuotes) == 0 && len(r.Padded) == 0 && len(r.Long) == 0 && len(r.Skipped) == 0
+}
+
+// ReadCSVLenient reads CSV that strict parsing would reject. A line with invalid quotes
+// is split at every delimiter with its quotes kept as they are, and parsing resumes on
+// the next line. Rows may have any number of fields, and rows shorter than the header
+// are padded with empty cells. With skipMalformed, rows with invalid quotes or a field
+// count different from the header are skipped instead. The header row is never skipped.
+func ReadCSVLenient(r io.Reader, delimiter rune, skipMalformed bool) ([][]string, LenientReport, error) {
+	var report LenientReport
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, report, err
+	}
+
+	var records [][]string
+	width := 0
+	add := func(record []string, line int, strayQuotes bool) {
+		if len(records) == 0 {
+			width = len(record)
+			if strayQuotes {
+				report.StrayQuotes = append(report.StrayQuotes, line)
+			}
+			records = append(records, record)
+			return
+		}
+
+		if skipMalformed && (strayQuotes || len(record) != width) {
+			report.Skipped = append(report.Skipped, line)
+			return
+		}
+		if strayQuotes {
+			report.StrayQuotes = append(report.StrayQuotes, line)
+		}
+		switch {
+		case len(record) < width:
+			record = fitRow(record, width)
+			report.Padded = append(report.Padded, line)
+		case len(record) > width:
+			report.Long = append(report.Long, line)
+		}
+		records = append(records, record)
+	}
+
+	// Parse strictly from offset; on a quote error, repair that line and start over after it
+	offset, firstLine := 0, 1
+	for offset < len(data) {
+		reader := csv.NewReader(bytes.NewReader(data[offset:]))
+		reader.Comma = delimiter
+		reader.FieldsPerRecord = -1
+
+		restart := false
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) {
+					return nil, report, err
+				}
+				// Split the raw line where the bad record starts and continue after it
+				start := lineOffset(data[offset:], parseErr.StartLine)
+				end := start + lineLength(data[offset+start:])
+				raw := bytes.TrimRight(data[offset+start:offset+end], "\r\n")
+				add(splitLiteral(string(raw), delimiter), firstLine+parseErr.StartLine-1, true)
+
+				firstLine += parseErr.StartLine
+				offset += end
+				restart = true
+				break
+			}
+			line, _ := reader.FieldPos(0)
+			add(record, firstLine+line-1, false)
+		}
+		if !restart {
+			break
+		}
+	}
+	return records, report, nil
+}
+
+// lineOffset returns the byte offset of the 1-indexed line in data
+func lineOffset(data []byte, line int) int {
+	offset := 0
+	for i := 1; i < line; i++ {
+		n := bytes.IndexByte(data[offset:], '\n')
+		if n < 0 {
+			return len(data)
+		}
+		offset += n + 1
+	}
+	return offset
+}
+
+// lineLength returns the length of the first line of data including its line break
+func lineLength(data []byte) int {
+	if n := bytes.IndexByte(data, '\n'); n >= 0 {
+		return n + 1
+	}
+	return len(data)
+}
+
+// splitLiteral splits a line at every delimiter. Quotes are kept as they are, except
+// around a field that is entirely enclosed in them.
+func splitLiteral(line string, delimiter rune) []string {
+	fields := strings.Split(line, string(delimiter))
+	for i, f := range fields {
+		if len(f) >= 2 && strings.HasPrefix(f, `"`) && strings.HasSuffix(f, `"`) {
+			fields[i] = strings.ReplaceAll(f[1:len(f)-1], `""`, `"`)
+		}
+	}
+	return fields
+}
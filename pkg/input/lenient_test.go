@@ -0,0 +1,76 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVLenient(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		delimiter     rune
+		skipMalformed bool
+		want          [][]string
+		wantReport    LenientReport
+	}{
+		{
+			name:      "valid CSV",
+			input:     "a,b\n\"1,5\",2\n",
+			delimiter: ',',
+			want:      [][]string{{"a", "b"}, {"1,5", "2"}},
+		},
+		{
+			name:       "stray quote",
+			input:      "name,size\n12\" pipe,3\nnut,1\n",
+			delimiter:  ',',
+			want:       [][]string{{"name", "size"}, {`12" pipe`, "3"}, {"nut", "1"}},
+			wantReport: LenientReport{StrayQuotes: []int{2}},
+		},
+		{
+			name:       "unterminated quote only spoils its line",
+			input:      "a,b\n\"x,1\ny,2\n",
+			delimiter:  ',',
+			want:       [][]string{{"a", "b"}, {`"x`, "1"}, {"y", "2"}},
+			wantReport: LenientReport{StrayQuotes: []int{2}},
+		},
+		{
+			name:       "short and long rows",
+			input:      "a,b,c\n1\n1,2,3,4\n",
+			delimiter:  ',',
+			want:       [][]string{{"a", "b", "c"}, {"1", "", ""}, {"1", "2", "3", "4"}},
+			wantReport: LenientReport{Padded: []int{2}, Long: []int{3}},
+		},
+		{
+			name:          "skip malformed rows",
+			input:         "a,b\n1\n1,2\n3\" x,4\n5,6,7\n",
+			delimiter:     ',',
+			skipMalformed: true,
+			want:          [][]string{{"a", "b"}, {"1", "2"}},
+			wantReport:    LenientReport{Skipped: []int{2, 4, 5}},
+		},
+		{
+			name:       "line numbers after multi-line fields",
+			input:      "a;b\n\"x\ny\";1\nz\";2\n",
+			delimiter:  ';',
+			want:       [][]string{{"a", "b"}, {"x\ny", "1"}, {`z"`, "2"}},
+			wantReport: LenientReport{StrayQuotes: []int{4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report, err := ReadCSVLenient(strings.NewReader(tt.input), tt.delimiter, tt.skipMalformed)
+			if err != nil {
+				t.Fatalf("ReadCSVLenient() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadCSVLenient() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(report, tt.wantReport) {
+				t.Errorf("ReadCSVLenient() report = %+v, want %+v", report, tt.wantReport)
+			}
+		})
+	}
+}
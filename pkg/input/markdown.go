@@ -0,0 +1,130 @@
+package input
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Table is a table found in a document
+type Table struct {
+	// Name is the caption or the heading above the table (empty if there is none)
+	Name string
+	// Rows holds the cell text, starting with the header row
+	Rows [][]string
+}
+
+// delimiterRowPattern matches the delimiter row below the header of a pipe table, e.g. "|---|:--:|"
+var delimiterRowPattern = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+// headingPattern matches an ATX heading such as "## Results"
+var headingPattern = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+
+// ReadMarkdownTables extracts the pipe tables (GitHub Flavored Markdown) from a
+// Markdown document. Each table is named after the nearest heading above it.
+// Rows are padded or truncated to the number of header cells, and tables inside
+// fenced code blocks are ignored.
+func ReadMarkdownTables(r io.Reader) ([]Table, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	var tables []Table
+	heading := ""
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// Skip fenced code blocks
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence = line[:3]
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			heading = m[1]
+			continue
+		}
+
+		// A table starts with a header row followed by a delimiter row
+		if !strings.Contains(line, "|") || i+1 >= len(lines) {
+			continue
+		}
+		delimiterRow := strings.TrimSpace(lines[i+1])
+		if !delimiterRowPattern.MatchString(delimiterRow) {
+			continue
+		}
+		header := splitPipeRow(line)
+		if len(header) != len(splitPipeRow(delimiterRow)) {
+			continue
+		}
+
+		table := Table{Name: heading, Rows: [][]string{header}}
+		i += 2
+		for ; i < len(lines); i++ {
+			row := strings.TrimSpace(lines[i])
+			if row == "" || !strings.Contains(row, "|") {
+				break
+			}
+			table.Rows = append(table.Rows, fitRow(splitPipeRow(row), len(header)))
+		}
+		// Let the loop see the line that ended the table
+		i--
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// splitPipeRow splits a pipe table row into trimmed cells. Leading and trailing
+// pipes are optional, and "\|" is a literal pipe inside a cell.
+func splitPipeRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			if r != '|' {
+				cell.WriteRune('\\')
+			}
+			cell.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteRune(r)
+		}
+	}
+	if escaped {
+		cell.WriteRune('\\')
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// fitRow pads row with empty cells or truncates it to n cells
+func fitRow(row []string, n int) []string {
+	if len(row) > n {
+		return row[:n]
+	}
+	for len(row) < n {
+		row = append(row, "")
+	}
+	return row
+}
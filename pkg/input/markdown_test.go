@@ -0,0 +1,74 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMarkdownTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Table
+	}{
+		{
+			name: "alignment row",
+			input: "| Name | Qty | Price |\n" +
+				"|:-----|:---:|------:|\n" +
+				"| a    | 1   | 100   |\n",
+			want: []Table{{Rows: [][]string{{"Name", "Qty", "Price"}, {"a", "1", "100"}}}},
+		},
+		{
+			name:  "without outer pipes",
+			input: "a | b\n--- | ---\n1 | 2\n",
+			want:  []Table{{Rows: [][]string{{"a", "b"}, {"1", "2"}}}},
+		},
+		{
+			name:  "escaped pipes",
+			input: "| cmd | note |\n|---|---|\n| a \\| b | c\\d |\n",
+			want:  []Table{{Rows: [][]string{{"cmd", "note"}, {"a | b", `c\d`}}}},
+		},
+		{
+			name:  "rows are padded and truncated to the header",
+			input: "| a | b |\n|---|---|\n| 1 |\n| 1 | 2 | 3 |\n",
+			want:  []Table{{Rows: [][]string{{"a", "b"}, {"1", ""}, {"1", "2"}}}},
+		},
+		{
+			name: "several tables named after headings",
+			input: "# Report\n\n## Sales\n\n| a |\n|---|\n| 1 |\n\nText\n\n" +
+				"### Costs ###\n| b |\n|---|\n| 2 |\n| c |\n",
+			want: []Table{
+				{Name: "Sales", Rows: [][]string{{"a"}, {"1"}}},
+				{Name: "Costs", Rows: [][]string{{"b"}, {"2"}, {"c"}}},
+			},
+		},
+		{
+			name:  "tables in code blocks are ignored",
+			input: "```\n| a |\n|---|\n| 1 |\n```\n~~~md\n| b |\n|---|\n~~~\n| c |\n|---|\n| 3 |\n",
+			want:  []Table{{Rows: [][]string{{"c"}, {"3"}}}},
+		},
+		{
+			name:  "header and delimiter with different widths",
+			input: "| a | b |\n|---|\n| 1 | 2 |\n",
+			want:  nil,
+		},
+		{
+			name:  "CRLF line breaks",
+			input: "| a |\r\n|---|\r\n| 1 |\r\n",
+			want:  []Table{{Rows: [][]string{{"a"}, {"1"}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadMarkdownTables(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadMarkdownTables() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMarkdownTables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field types of a schema
+const (
+	FieldString   = "string"
+	FieldInteger  = "integer"
+	FieldNumber   = "number"
+	FieldBoolean  = "boolean"
+	FieldDate     = "date"
+	FieldDateTime = "datetime"
+	FieldAny      = "any"
+)
+
+// Schema is a table schema compatible with the Frictionless Table Schema specification
+// (https://specs.frictionlessdata.io/table-schema/). Only the properties below are used.
+type Schema struct {
+	// Fields describes the columns, matched to the header row by name
+	Fields []*SchemaField `json:"fields"`
+	// MissingValues are the cell values treated as empty (default: "")
+	MissingValues []string `json:"missingValues"`
+}
+
+// SchemaField describes a column of a schema
+type SchemaField struct {
+	// Name is the header name of the column
+	Name string `json:"name"`
+	// Type is the field type (default: string)
+	Type string `json:"type"`
+	// Format is how dates are written: "default", "any" or a strptime pattern such as "%d/%m/%Y"
+	Format string `json:"format"`
+	// Constraints restricts the values of the column
+	Constraints FieldConstraints `json:"constraints"`
+	// DecimalChar is the decimal separator of numbers (default: ".")
+	DecimalChar string `json:"decimalChar"`
+	// GroupChar is the thousands separator of numbers (default: none)
+	GroupChar string `json:"groupChar"`
+	// BareNumber set to false allows text around numbers such as "$" or "%" (default: true)
+	BareNumber *bool `json:"bareNumber"`
+	// TrueValues are the values read as true (default: true, True, TRUE, 1)
+	TrueValues []string `json:"trueValues"`
+	// FalseValues are the values read as false (default: false, False, FALSE, 0)
+	FalseValues []string `json:"falseValues"`
+	// NumberFormat is the Google Sheets number format pattern of the column, e.g. "#,##0.00".
+	// This is a gs-write extension to the specification.
+	NumberFormat string `json:"numberFormat"`
+
+	layouts []string
+	pattern *regexp.Regexp
+	enum    []interface{}
+}
+
+// FieldConstraints restricts the values of a column
+type FieldConstraints struct {
+	// Required rejects empty values
+	Required bool `json:"required"`
+	// Enum lists the allowed values
+	Enum []interface{} `json:"enum"`
+	// Pattern is a regular expression the whole value must match
+	Pattern string `json:"pattern"`
+	// Minimum is the smallest allowed number
+	Minimum *float64 `json:"minimum"`
+	// Maximum is the largest allowed number
+	Maximum *float64 `json:"maximum"`
+}
+
+// InvalidRow is a row that failed validation against a schema
+type InvalidRow struct {
+	// Row is the 0-indexed row
+	Row int
+	// Errors describes each failure
+	Errors []string
+}
+
+// SchemaResult is the result of Schema.Apply
+type SchemaResult struct {
+	// Values holds the typed values of the rows; the header row and columns not in the
+	// schema are kept as strings, and cells of invalid rows that fail are kept as they are
+	Values [][]interface{}
+	// Fields is the schema field of each column of the header row (nil if not in the schema)
+	Fields []*SchemaField
+	// Invalid lists the rows that failed validation
+	Invalid []InvalidRow
+}
+
+// Default values of boolean fields
+var (
+	defaultTrueValues  = []string{"true", "True", "TRUE", "1"}
+	defaultFalseValues = []string{"false", "False", "FALSE", "0"}
+)
+
+// Layouts of dates in the default format
+var (
+	defaultDateLayouts     = []string{"2006-01-02"}
+	defaultDateTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05"}
+)
+
+// strptimeDirectives maps strptime directives to Go layout elements
+var strptimeDirectives = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'b': "Jan", 'B': "January",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM", 'z': "-0700", '%': "%",
+}
+
+// ReadSchema reads a table schema from JSON
+func ReadSchema(r io.Reader) (*Schema, error) {
+	var schema Schema
+	if err := json.NewDecoder(r).Decode(&schema); err != nil {
+		return nil, err
+	}
+	if len(schema.Fields) == 0 {
+		return nil, fmt.Errorf("schema has no fields")
+	}
+	if schema.MissingValues == nil {
+		schema.MissingValues = []string{""}
+	}
+
+	for _, field := range schema.Fields {
+		if field.Name == "" {
+			return nil, fmt.Errorf("schema field without a name")
+		}
+		if err := field.compile(); err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+	}
+	return &schema, nil
+}
+
+// compile checks the field and prepares its date layouts, pattern and allowed values
+func (f *SchemaField) compile() error {
+	if f.Type == "" {
+		f.Type = FieldString
+	}
+	switch f.Type {
+	case FieldString, FieldInteger, FieldNumber, FieldBoolean, FieldAny:
+	case FieldDate, FieldDateTime:
+		layouts, err := dateLayoutsFor(f.Type, f.Format)
+		if err != nil {
+			return err
+		}
+		f.layouts = layouts
+	default:
+		return fmt.Errorf("unsupported type: %s (supported: string, integer, number, boolean, date, datetime, any)", f.Type)
+	}
+	if f.DecimalChar == "" {
+		f.DecimalChar = "."
+	}
+	if f.TrueValues == nil {
+		f.TrueValues = defaultTrueValues
+	}
+	if f.FalseValues == nil {
+		f.FalseValues = defaultFalseValues
+	}
+
+	if f.Constraints.Pattern != "" {
+		pattern, err := regexp.Compile(`^(?:` + f.Constraints.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		f.pattern = pattern
+	}
+	for _, value := range f.Constraints.Enum {
+		if s, ok := value.(string); ok {
+			v, err := f.cast(s)
+			if err != nil {
+				return fmt.Errorf("invalid enum value %q: %w", s, err)
+			}
+			value = v
+		}
+		f.enum = append(f.enum, value)
+	}
+	return nil
+}
+
+// dateLayoutsFor returns the Go layouts for a date or datetime field format
+func dateLayoutsFor(fieldType, format string) ([]string, error) {
+	switch format {
+	case "", "default":
+		if fieldType == FieldDate {
+			return defaultDateLayouts, nil
+		}
+		return defaultDateTimeLayouts, nil
+	case "any":
+		if fieldType == FieldDate {
+			return dateLayouts, nil
+		}
+		return append(append([]string{}, dateTimeLayouts...), dateLayouts...), nil
+	}
+
+	var layout strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			layout.WriteByte(format[i])
+			continue
+		}
+		if i+1 == len(format) {
+			return nil, fmt.Errorf("invalid format: %s", format)
+		}
+		i++
+		element, ok := strptimeDirectives[format[i]]
+		if !ok {
+			return nil, fmt.Errorf("unsupported directive %%%c in format: %s", format[i], format)
+		}
+		layout.WriteString(element)
+	}
+	return []string{layout.String()}, nil
+}
+
+// Apply validates the rows after the header row against the schema and converts their
+// cells to typed values. It returns an error if a schema field is not in the header.
+func (s *Schema) Apply(rows [][]string) (*SchemaResult, error) {
+	if len(rows) == 0 {
+		return &SchemaResult{}, nil
+	}
+
+	header := rows[0]
+	result := &SchemaResult{Fields: make([]*SchemaField, len(header))}
+	for _, field := range s.Fields {
+		col := -1
+		for i, name := range header {
+			if name == field.Name {
+				col = i
+				break
+			}
+		}
+		if col < 0 {
+			return nil, fmt.Errorf("schema field %q not found in the header", field.Name)
+		}
+		result.Fields[col] = field
+	}
+
+	result.Values = make([][]interface{}, len(rows))
+	result.Values[0] = make([]interface{}, len(header))
+	for i, name := range header {
+		result.Values[0][i] = name
+	}
+	for r, row := range rows[1:] {
+		values := make([]interface{}, len(row))
+		var errs []string
+		for col, field := range result.Fields {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+			if field == nil {
+				if col < len(row) {
+					values[col] = cell
+				}
+				continue
+			}
+			value, err := field.validate(cell, s.isMissing(cell))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", field.Name, err))
+				value = cell
+			}
+			if col < len(row) {
+				values[col] = value
+			}
+		}
+		for col := len(result.Fields); col < len(row); col++ {
+			values[col] = row[col]
+		}
+		result.Values[r+1] = values
+		if len(errs) > 0 {
+			result.Invalid = append(result.Invalid, InvalidRow{Row: r + 1, Errors: errs})
+		}
+	}
+	return result, nil
+}
+
+// isMissing reports whether the cell is one of the missing values
+func (s *Schema) isMissing(cell string) bool {
+	for _, missing := range s.MissingValues {
+		if cell == missing {
+			return true
+		}
+	}
+	return false
+}
+
+// validate converts a cell to the field type and checks the constraints. Missing
+// cells become nil.
+func (f *SchemaField) validate(cell string, missing bool) (interface{}, error) {
+	if missing {
+		if f.Constraints.Required {
+			return nil, fmt.Errorf("value is required")
+		}
+		return nil, nil
+	}
+
+	value, err := f.cast(cell)
+	if err != nil {
+		return nil, err
+	}
+	if f.pattern != nil && !f.pattern.MatchString(cell) {
+		return nil, fmt.Errorf("%q does not match the pattern %s", cell, f.Constraints.Pattern)
+	}
+	if f.enum != nil && !containsValue(f.enum, value) {
+		return nil, fmt.Errorf("%q is not one of the allowed values", cell)
+	}
+	if number, ok := value.(float64); ok {
+		if minimum := f.Constraints.Minimum; minimum != nil && number < *minimum {
+			return nil, fmt.Errorf("%s is less than the minimum %v", cell, *minimum)
+		}
+		if maximum := f.Constraints.Maximum; maximum != nil && number > *maximum {
+			return nil, fmt.Errorf("%s is greater than the maximum %v", cell, *maximum)
+		}
+	}
+	return value, nil
+}
+
+// cast converts a cell to the field type: float64 for integer and number, bool,
+// time.Time for date and datetime, and string otherwise
+func (f *SchemaField) cast(cell string) (interface{}, error) {
+	switch f.Type {
+	case FieldInteger, FieldNumber:
+		text := f.bareNumber(cell)
+		if f.Type == FieldInteger {
+			n, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not an integer", cell)
+			}
+			return float64(n), nil
+		}
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", cell)
+		}
+		return n, nil
+	case FieldBoolean:
+		for _, v := range f.TrueValues {
+			if cell == v {
+				return true, nil
+			}
+		}
+		for _, v := range f.FalseValues {
+			if cell == v {
+				return false, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a boolean", cell)
+	case FieldDate, FieldDateTime:
+		if t, ok := parseTime(cell, f.layouts); ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("%q is not a %s in the expected format", cell, f.Type)
+	default:
+		return cell, nil
+	}
+}
+
+// nonNumberPattern matches the text around a number that is not bare
+var nonNumberPattern = regexp.MustCompile(`^[^0-9+\-.]*|[^0-9]*$`)
+
+// bareNumber removes the group separators from a number, normalizes its decimal
+// separator and, unless the field requires bare numbers, removes the text around it
+func (f *SchemaField) bareNumber(cell string) string {
+	text := strings.TrimSpace(cell)
+	if f.BareNumber != nil && !*f.BareNumber {
+		text = nonNumberPattern.ReplaceAllString(text, "")
+	}
+	if f.GroupChar != "" {
+		text = strings.ReplaceAll(text, f.GroupChar, "")
+	}
+	if f.DecimalChar != "." {
+		text = strings.ReplaceAll(text, f.DecimalChar, ".")
+	}
+	return text
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if t, ok := v.(time.Time); ok {
+			if u, ok := value.(time.Time); ok && t.Equal(u) {
+				return true
+			}
+			continue
+		}
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
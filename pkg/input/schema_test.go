@@ -0,0 +1,166 @@
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemaApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		rows        [][]string
+		want        [][]interface{}
+		wantInvalid []int
+	}{
+		{
+			name:   "types",
+			schema: `{"fields": [{"name": "id", "type": "integer"}, {"name": "price", "type": "number"}, {"name": "paid", "type": "boolean"}, {"name": "note"}]}`,
+			rows:   [][]string{{"id", "price", "paid", "note"}, {"1", "1.5", "true", "ok"}, {"2", "", "0", ""}},
+			want:   [][]interface{}{{"id", "price", "paid", "note"}, {1.0, 1.5, true, "ok"}, {2.0, nil, false, nil}},
+		},
+		{
+			name:        "invalid values are kept as they are",
+			schema:      `{"fields": [{"name": "id", "type": "integer"}]}`,
+			rows:        [][]string{{"id"}, {"1.5"}, {"x"}},
+			want:        [][]interface{}{{"id"}, {"1.5"}, {"x"}},
+			wantInvalid: []int{1, 2},
+		},
+		{
+			name:        "required",
+			schema:      `{"fields": [{"name": "id", "constraints": {"required": true}}], "missingValues": ["", "NA"]}`,
+			rows:        [][]string{{"id"}, {"a"}, {""}, {"NA"}},
+			want:        [][]interface{}{{"id"}, {"a"}, {""}, {"NA"}},
+			wantInvalid: []int{2, 3},
+		},
+		{
+			name:        "enum",
+			schema:      `{"fields": [{"name": "size", "type": "integer", "constraints": {"enum": ["1", 2]}}]}`,
+			rows:        [][]string{{"size"}, {"1"}, {"2"}, {"3"}},
+			want:        [][]interface{}{{"size"}, {1.0}, {2.0}, {"3"}},
+			wantInvalid: []int{3},
+		},
+		{
+			name:        "pattern matches the whole value",
+			schema:      `{"fields": [{"name": "code", "constraints": {"pattern": "[A-Z]{3}"}}]}`,
+			rows:        [][]string{{"code"}, {"ABC"}, {"ABCD"}, {"abc"}},
+			want:        [][]interface{}{{"code"}, {"ABC"}, {"ABCD"}, {"abc"}},
+			wantInvalid: []int{2, 3},
+		},
+		{
+			name:        "minimum and maximum",
+			schema:      `{"fields": [{"name": "n", "type": "number", "constraints": {"minimum": 0, "maximum": 10}}]}`,
+			rows:        [][]string{{"n"}, {"0"}, {"10"}, {"-1"}, {"11"}},
+			want:        [][]interface{}{{"n"}, {0.0}, {10.0}, {"-1"}, {"11"}},
+			wantInvalid: []int{3, 4},
+		},
+		{
+			name:   "bareNumber false allows text around numbers",
+			schema: `{"fields": [{"name": "price", "type": "number", "bareNumber": false, "groupChar": ",", "decimalChar": "."}]}`,
+			rows:   [][]string{{"price"}, {"$1,234.50"}, {"12%"}},
+			want:   [][]interface{}{{"price"}, {1234.5}, {12.0}},
+		},
+		{
+			name:        "bare numbers by default",
+			schema:      `{"fields": [{"name": "price", "type": "number"}]}`,
+			rows:        [][]string{{"price"}, {"$1"}},
+			want:        [][]interface{}{{"price"}, {"$1"}},
+			wantInvalid: []int{1},
+		},
+		{
+			name:   "decimal comma",
+			schema: `{"fields": [{"name": "price", "type": "number", "decimalChar": ",", "groupChar": "."}]}`,
+			rows:   [][]string{{"price"}, {"1.234,5"}},
+			want:   [][]interface{}{{"price"}, {1234.5}},
+		},
+		{
+			name:        "default date format",
+			schema:      `{"fields": [{"name": "day", "type": "date"}]}`,
+			rows:        [][]string{{"day"}, {"2024-12-31"}, {"2024/12/31"}},
+			want:        [][]interface{}{{"day"}, {time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)}, {"2024/12/31"}},
+			wantInvalid: []int{2},
+		},
+		{
+			name:   "any date format",
+			schema: `{"fields": [{"name": "day", "type": "date", "format": "any"}]}`,
+			rows:   [][]string{{"day"}, {"2024/12/31"}},
+			want:   [][]interface{}{{"day"}, {time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:        "strptime date format",
+			schema:      `{"fields": [{"name": "day", "type": "date", "format": "%d/%m/%Y"}]}`,
+			rows:        [][]string{{"day"}, {"31/12/2024"}, {"2024-12-31"}},
+			want:        [][]interface{}{{"day"}, {time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)}, {"2024-12-31"}},
+			wantInvalid: []int{2},
+		},
+		{
+			name:   "strptime datetime format",
+			schema: `{"fields": [{"name": "at", "type": "datetime", "format": "%Y-%m-%d %H:%M"}]}`,
+			rows:   [][]string{{"at"}, {"2024-12-31 23:59"}},
+			want:   [][]interface{}{{"at"}, {time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC)}},
+		},
+		{
+			name:   "columns not in the schema stay text",
+			schema: `{"fields": [{"name": "id", "type": "integer"}]}`,
+			rows:   [][]string{{"name", "id"}, {"a", "1", "extra"}},
+			want:   [][]interface{}{{"name", "id"}, {"a", 1.0, "extra"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ReadSchema(strings.NewReader(tt.schema))
+			if err != nil {
+				t.Fatalf("ReadSchema() error = %v", err)
+			}
+			result, err := schema.Apply(tt.rows)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Values, tt.want) {
+				t.Errorf("Apply() values = %v, want %v", result.Values, tt.want)
+			}
+			var invalid []int
+			for _, row := range result.Invalid {
+				invalid = append(invalid, row.Row)
+			}
+			if !reflect.DeepEqual(invalid, tt.wantInvalid) {
+				t.Errorf("Apply() invalid rows = %v, want %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestSchemaApplyMissingField(t *testing.T) {
+	schema, err := ReadSchema(strings.NewReader(`{"fields": [{"name": "id"}]}`))
+	if err != nil {
+		t.Fatalf("ReadSchema() error = %v", err)
+	}
+	if _, err := schema.Apply([][]string{{"name"}, {"a"}}); err == nil {
+		t.Error("Apply() error = nil, want an error for a field missing from the header")
+	}
+}
+
+func TestReadSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"no fields", `{"fields": []}`},
+		{"field without a name", `{"fields": [{"type": "string"}]}`},
+		{"unsupported type", `{"fields": [{"name": "a", "type": "duration"}]}`},
+		{"unsupported directive", `{"fields": [{"name": "a", "type": "date", "format": "%Q"}]}`},
+		{"invalid pattern", `{"fields": [{"name": "a", "constraints": {"pattern": "("}}]}`},
+		{"invalid enum value", `{"fields": [{"name": "a", "type": "integer", "constraints": {"enum": ["x"]}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSchema(strings.NewReader(tt.schema)); err == nil {
+				t.Error("ReadSchema() error = nil, want an error")
+			}
+		})
+	}
+}
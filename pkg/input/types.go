@@ -0,0 +1,165 @@
+package input
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column types inferred by InferColumnTypes
+const (
+	TypeText     = "text"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeDate     = "date"
+	TypeDateTime = "datetime"
+)
+
+// Number patterns. Numbers with a leading zero such as postal codes and employee IDs
+// do not match, so that they stay text.
+var (
+	intPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	floatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// maxIntDigits is the number of digits a spreadsheet number holds exactly; longer
+// integers such as card numbers stay text
+const maxIntDigits = 15
+
+// Layouts of date and date-time cells
+var (
+	dateLayouts     = []string{"2006-01-02", "2006/01/02"}
+	dateTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006/01/02 15:04:05",
+		"2006-01-02 15:04",
+		"2006/01/02 15:04",
+	}
+)
+
+// InferColumnTypes returns the type of each column of rows, inferred from the rows
+// after the header row. A column has a type only if all its non-empty cells have it;
+// int and float columns become float, and date and datetime columns become datetime.
+// Columns with no non-empty cells are text.
+func InferColumnTypes(rows [][]string) []string {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	types := make([]string, width)
+	for col := range types {
+		columnType := ""
+		for _, row := range rows[min(1, len(rows)):] {
+			if col >= len(row) || row[col] == "" {
+				continue
+			}
+			columnType = mergeTypes(columnType, cellType(row[col]))
+			if columnType == TypeText {
+				break
+			}
+		}
+		if columnType == "" {
+			columnType = TypeText
+		}
+		types[col] = columnType
+	}
+	return types
+}
+
+// TypedValues converts the cells of rows after the header row to values of the column
+// types: float64 for int and float, bool, time.Time for date and datetime, and string
+// for text. Empty cells become nil.
+func TypedValues(rows [][]string, types []string) [][]interface{} {
+	values := make([][]interface{}, len(rows))
+	for r, row := range rows {
+		values[r] = make([]interface{}, len(row))
+		for col, cell := range row {
+			if r == 0 || col >= len(types) {
+				values[r][col] = cell
+				continue
+			}
+			values[r][col] = typedValue(cell, types[col])
+		}
+	}
+	return values
+}
+
+// typedValue converts a cell to a value of the column type
+func typedValue(cell, columnType string) interface{} {
+	if cell == "" {
+		return nil
+	}
+	switch columnType {
+	case TypeInt, TypeFloat:
+		if f, err := strconv.ParseFloat(cell, 64); err == nil {
+			return f
+		}
+	case TypeBool:
+		return strings.EqualFold(cell, "true")
+	case TypeDate:
+		if t, ok := parseTime(cell, dateLayouts); ok {
+			return t
+		}
+	case TypeDateTime:
+		if t, ok := parseTime(cell, dateTimeLayouts); ok {
+			return t
+		}
+		if t, ok := parseTime(cell, dateLayouts); ok {
+			return t
+		}
+	}
+	return cell
+}
+
+// cellType returns the type of a non-empty cell
+func cellType(cell string) string {
+	switch {
+	case intPattern.MatchString(cell):
+		if len(strings.TrimPrefix(cell, "-")) > maxIntDigits {
+			return TypeText
+		}
+		return TypeInt
+	case floatPattern.MatchString(cell):
+		return TypeFloat
+	case strings.EqualFold(cell, "true") || strings.EqualFold(cell, "false"):
+		return TypeBool
+	}
+	if _, ok := parseTime(cell, dateLayouts); ok {
+		return TypeDate
+	}
+	if _, ok := parseTime(cell, dateTimeLayouts); ok {
+		return TypeDateTime
+	}
+	return TypeText
+}
+
+// mergeTypes returns the type of a column with cells of both types ("" means no cells yet)
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case (a == TypeInt && b == TypeFloat) || (a == TypeFloat && b == TypeInt):
+		return TypeFloat
+	case (a == TypeDate && b == TypeDateTime) || (a == TypeDateTime && b == TypeDate):
+		return TypeDateTime
+	default:
+		return TypeText
+	}
+}
+
+// parseTime parses s with the first matching layout
+func parseTime(s string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
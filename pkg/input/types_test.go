@@ -0,0 +1,86 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInferColumnTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want []string
+	}{
+		{
+			name: "one type per column",
+			rows: [][]string{
+				{"int", "float", "bool", "date", "datetime", "text"},
+				{"1", "1.5", "true", "2024-01-02", "2024-01-02 10:00:00", "a"},
+				{"-20", "2e3", "FALSE", "2024/01/03", "2024-01-02T10:00:00+09:00", "b"},
+			},
+			want: []string{TypeInt, TypeFloat, TypeBool, TypeDate, TypeDateTime, TypeText},
+		},
+		{
+			name: "the header row is ignored",
+			rows: [][]string{{"1", "x"}, {"a", "2"}},
+			want: []string{TypeText, TypeInt},
+		},
+		{
+			name: "mixed types",
+			rows: [][]string{{"a", "b", "c"}, {"1", "2024-01-02", "1"}, {"1.5", "2024-01-02 10:00", "x"}},
+			want: []string{TypeFloat, TypeDateTime, TypeText},
+		},
+		{
+			name: "empty cells are ignored",
+			rows: [][]string{{"a", "b"}, {"", "1"}, {"2"}},
+			want: []string{TypeInt, TypeInt},
+		},
+		{
+			name: "leading zeros and long integers stay text",
+			rows: [][]string{{"zip", "card"}, {"0123", "1234567890123456"}},
+			want: []string{TypeText, TypeText},
+		},
+		{
+			name: "empty columns are text",
+			rows: [][]string{{"a", "b"}, {"", "1"}},
+			want: []string{TypeText, TypeInt},
+		},
+		{
+			name: "header only",
+			rows: [][]string{{"a"}},
+			want: []string{TypeText},
+		},
+		{
+			name: "no rows",
+			rows: nil,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferColumnTypes(tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InferColumnTypes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedValues(t *testing.T) {
+	rows := [][]string{
+		{"n", "ok", "day", "at", "name"},
+		{"1", "true", "2024-01-02", "2024-01-02", "a"},
+		{"", "FALSE", "2024/01/03", "2024-01-02 10:00", ""},
+	}
+	types := []string{TypeInt, TypeBool, TypeDate, TypeDateTime, TypeText}
+	want := [][]interface{}{
+		{"n", "ok", "day", "at", "name"},
+		{1.0, true, time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), "a"},
+		{nil, false, time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC), nil},
+	}
+
+	if got := TypedValues(rows, types); !reflect.DeepEqual(got, want) {
+		t.Errorf("TypedValues() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,483 @@
+package input
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxMagic is the signature at the beginning of an xlsx (zip) file
+var xlsxMagic = []byte("PK\x03\x04")
+
+// excelEpoch is day 0 of the 1900 date system (serial numbers count days from here)
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// excel1904Offset is the number of days between the 1900 and 1904 date systems
+const excel1904Offset = 1462
+
+// Worksheet is a worksheet read from an xlsx workbook
+type Worksheet struct {
+	// Name is the worksheet name
+	Name string
+	// Rows holds the cell text
+	Rows [][]string
+	// Values holds the typed cell values: string, float64, bool, time.Time (dates),
+	// time.Duration (times of day and elapsed times) or nil (empty)
+	Values [][]interface{}
+}
+
+// IsXLSX reports whether data starts with the xlsx (zip) signature
+func IsXLSX(data []byte) bool {
+	return bytes.HasPrefix(data, xlsxMagic)
+}
+
+// ReadXLSX reads the worksheets of an xlsx workbook. If names is not empty, only the
+// worksheets with those names are read, in the given order. Numbers, booleans and dates
+// are kept as typed values.
+func ReadXLSX(r io.ReaderAt, size int64, names []string) ([]Worksheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid xlsx file: %w", err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := readXMLFile(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := readXMLFile(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	var sst xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := readXMLFile(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+	}
+	var styles xlsxStyles
+	if _, ok := files["xl/styles.xml"]; ok {
+		if err := readXMLFile(files, "xl/styles.xml", &styles); err != nil {
+			return nil, err
+		}
+	}
+
+	sharedStrings := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		sharedStrings[i] = item.text()
+	}
+	dateStyles := styles.dateStyles()
+
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		targets[rel.ID] = rel.Target
+	}
+
+	selected := workbook.Sheets
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			found := false
+			for _, s := range workbook.Sheets {
+				if s.Name == name {
+					selected = append(selected, s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("worksheet %q not found", name)
+			}
+		}
+	}
+
+	var worksheets []Worksheet
+	for _, s := range selected {
+		target, ok := targets[s.RelID]
+		if !ok {
+			return nil, fmt.Errorf("worksheet %q has no relationship", s.Name)
+		}
+		// Targets are relative to xl/ unless absolute
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+
+		var sheet xlsxWorksheet
+		if err := readXMLFile(files, target, &sheet); err != nil {
+			return nil, err
+		}
+
+		ws, err := sheet.toWorksheet(s.Name, sharedStrings, dateStyles, workbook.Properties.Date1904)
+		if err != nil {
+			return nil, fmt.Errorf("worksheet %q: %w", s.Name, err)
+		}
+		worksheets = append(worksheets, ws)
+	}
+	return worksheets, nil
+}
+
+// readXMLFile decodes the XML file with the given name in the archive into v
+func readXMLFile(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("not a valid xlsx file: %s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxWorkbook is xl/workbook.xml
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string `xml:"name,attr"`
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships is xl/_rels/workbook.xml.rels
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxSharedStrings is xl/sharedStrings.xml
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText is a plain or rich text string. Phonetic runs (rPh) are ignored.
+type xlsxRichText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// text returns the string without formatting
+func (t xlsxRichText) text() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// xlsxStyles is xl/styles.xml
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// dateStyles returns, for each cell style index that formats a date or time, whether
+// the format includes a time of day, has no date or shows elapsed time
+func (s xlsxStyles) dateStyles() map[int]dateStyle {
+	custom := make(map[int]string)
+	for _, f := range s.NumFmts {
+		custom[f.ID] = f.Code
+	}
+
+	styles := make(map[int]dateStyle)
+	for i, xf := range s.CellXfs {
+		id := xf.NumFmtID
+		if code, ok := custom[id]; ok {
+			if isDateFormatCode(code) {
+				styles[i] = dateStyleOf(code)
+			}
+			continue
+		}
+		switch {
+		case id >= 14 && id <= 17, id >= 27 && id <= 31, id >= 34 && id <= 36, id >= 50 && id <= 58:
+			styles[i] = dateStyle{}
+		case id == 22:
+			styles[i] = dateStyle{withTime: true}
+		case id >= 18 && id <= 21, id == 32, id == 33, id == 45, id == 47:
+			// Time-only formats
+			styles[i] = dateStyle{withTime: true, timeOnly: true}
+		case id == 46:
+			// [h]:mm:ss
+			styles[i] = dateStyle{withTime: true, timeOnly: true, elapsed: true}
+		}
+	}
+	return styles
+}
+
+// dateStyle describes a date or time number format
+type dateStyle struct {
+	// withTime is set if the format includes a time of day
+	withTime bool
+	// timeOnly is set if the format has no date, e.g. "hh:mm"
+	timeOnly bool
+	// elapsed is set if the format shows elapsed time, e.g. "[h]:mm"
+	elapsed bool
+}
+
+// fractionalSecondsPattern matches seconds with fractions such as "ss.000", whose
+// digit placeholders belong to the time
+var fractionalSecondsPattern = regexp.MustCompile(`s\.0+`)
+
+// isDateFormatCode reports whether a custom number format code formats a date or time:
+// it has date or time tokens and no digit placeholders outside them
+func isDateFormatCode(code string) bool {
+	tokens := strings.ToLower(stripFormatLiterals(code))
+	tokens = fractionalSecondsPattern.ReplaceAllString(tokens, "s")
+	return strings.ContainsAny(tokens, "ymdhs") && !strings.ContainsAny(tokens, "0#?")
+}
+
+// dateStyleOf returns the date style of a custom date or time number format code
+func dateStyleOf(code string) dateStyle {
+	lower := strings.ToLower(code)
+	tokens := strings.ToLower(stripFormatLiterals(code))
+	elapsed := strings.Contains(lower, "[h") || strings.Contains(lower, "[m") || strings.Contains(lower, "[s")
+	withTime := elapsed || strings.ContainsAny(tokens, "hs")
+	return dateStyle{
+		withTime: withTime,
+		timeOnly: withTime && !strings.ContainsAny(tokens, "yd"),
+		elapsed:  elapsed,
+	}
+}
+
+// stripFormatLiterals removes quoted text, escaped characters and bracketed sections
+// (colors, locales) from a number format code
+func stripFormatLiterals(code string) string {
+	var b strings.Builder
+	inQuote, inBracket := false, false
+	escaped := false
+	for _, r := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote:
+			inQuote = r != '"'
+		case inBracket:
+			inBracket = r != ']'
+		case r == '"':
+			inQuote = true
+		case r == '[':
+			inBracket = true
+		case r == '\\':
+			escaped = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// xlsxWorksheet is xl/worksheets/sheetN.xml
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Style  int          `xml:"s,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// toWorksheet converts the parsed XML into rows of text and typed values
+func (ws xlsxWorksheet) toWorksheet(name string, sharedStrings []string, dateStyles map[int]dateStyle, date1904 bool) (Worksheet, error) {
+	result := Worksheet{Name: name}
+
+	nextRow := 0
+	for _, row := range ws.Rows {
+		rowIdx := nextRow
+		if row.R > 0 {
+			rowIdx = row.R - 1
+		}
+		nextRow = rowIdx + 1
+
+		// Fill skipped rows
+		for len(result.Rows) <= rowIdx {
+			result.Rows = append(result.Rows, nil)
+			result.Values = append(result.Values, nil)
+		}
+
+		nextCol := 0
+		for _, c := range row.Cells {
+			colIdx := nextCol
+			if c.Ref != "" {
+				col, err := columnFromRef(c.Ref)
+				if err != nil {
+					return Worksheet{}, err
+				}
+				colIdx = col
+			}
+			nextCol = colIdx + 1
+
+			text, value, err := cellValue(c.Type, c.Value, c.Inline, sharedStrings, dateStyles, c.Style, date1904)
+			if err != nil {
+				return Worksheet{}, fmt.Errorf("cell %s: %w", c.Ref, err)
+			}
+			if value == nil {
+				continue
+			}
+
+			for len(result.Rows[rowIdx]) <= colIdx {
+				result.Rows[rowIdx] = append(result.Rows[rowIdx], "")
+				result.Values[rowIdx] = append(result.Values[rowIdx], nil)
+			}
+			result.Rows[rowIdx][colIdx] = text
+			result.Values[rowIdx][colIdx] = value
+		}
+	}
+
+	// Drop trailing empty rows
+	for len(result.Rows) > 0 && len(result.Rows[len(result.Rows)-1]) == 0 {
+		result.Rows = result.Rows[:len(result.Rows)-1]
+		result.Values = result.Values[:len(result.Values)-1]
+	}
+	return result, nil
+}
+
+// cellValue converts a cell into its text and typed value (nil for empty cells)
+func cellValue(cellType, raw string, inline xlsxRichText, sharedStrings []string, dateStyles map[int]dateStyle, style int, date1904 bool) (string, interface{}, error) {
+	switch cellType {
+	case "s":
+		if raw == "" {
+			return "", nil, nil
+		}
+		idx, err := strconv.Atoi(raw)
+		if err != nil || idx < 0 || idx >= len(sharedStrings) {
+			return "", nil, fmt.Errorf("invalid shared string index %q", raw)
+		}
+		return sharedStrings[idx], sharedStrings[idx], nil
+	case "inlineStr":
+		text := inline.text()
+		return text, text, nil
+	case "str", "e":
+		// Formula results and errors are kept as text
+		if raw == "" {
+			return "", nil, nil
+		}
+		return raw, raw, nil
+	case "b":
+		b := raw == "1"
+		if b {
+			return "TRUE", true, nil
+		}
+		return "FALSE", false, nil
+	case "d":
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			t, err = time.Parse("2006-01-02T15:04:05", raw)
+		}
+		if err != nil {
+			return raw, raw, nil
+		}
+		return formatTime(t), t, nil
+	default:
+		if raw == "" {
+			return "", nil, nil
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return raw, raw, nil
+		}
+		if ds, ok := dateStyles[style]; ok && ds.timeOnly {
+			d := serialToDuration(n, ds.elapsed)
+			return formatDuration(d), d, nil
+		}
+		if ds, ok := dateStyles[style]; ok {
+			t := serialToTime(n, date1904)
+			if !ds.withTime {
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			}
+			return formatTime(t), t, nil
+		}
+		return raw, n, nil
+	}
+}
+
+// serialToTime converts an Excel serial date number into a time
+func serialToTime(serial float64, date1904 bool) time.Time {
+	if date1904 {
+		serial += excel1904Offset
+	}
+	days := math.Floor(serial)
+	// Round to the nearest second to avoid floating point noise
+	seconds := math.Round((serial - days) * 24 * 60 * 60)
+	return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+}
+
+// serialToDuration converts the time part of an Excel serial date number into the
+// time of day, or the whole number into an elapsed time if elapsed is set
+func serialToDuration(serial float64, elapsed bool) time.Duration {
+	if !elapsed {
+		serial -= math.Floor(serial)
+	}
+	// Round to the nearest second to avoid floating point noise
+	return time.Duration(math.Round(serial*24*60*60)) * time.Second
+}
+
+// formatDuration returns the text form of a time of day or elapsed time, e.g. "09:30:00"
+func formatDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+}
+
+// formatTime returns the text form of a date, with the time of day if it is not midnight
+func formatTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// columnFromRef returns the 0-indexed column of a cell reference such as "AB12"
+func columnFromRef(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
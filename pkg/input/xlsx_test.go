@@ -0,0 +1,110 @@
+package input
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadXLSXDates(t *testing.T) {
+	f, err := os.Open("testdata/dates.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worksheets, err := ReadXLSX(f, info.Size(), nil)
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	if len(worksheets) != 1 {
+		t.Fatalf("ReadXLSX() returned %d worksheets, want 1", len(worksheets))
+	}
+	ws := worksheets[0]
+
+	tests := []struct {
+		kind  string
+		text  string
+		value interface{}
+	}{
+		{"date", "2024-01-01", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"datetime", "2024-01-01 18:00:00", time.Date(2024, time.January, 1, 18, 0, 0, 0, time.UTC)},
+		{"time", "09:30:00", 9*time.Hour + 30*time.Minute},
+		{"elapsed", "36:00:00", 36 * time.Hour},
+		{"milliseconds", "2024-01-01 12:00:00", time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)},
+		{"tenths", "12:00:00", 12 * time.Hour},
+		{"number", "1.5", 1.5},
+		{"am/pm", "18:00:00", 18 * time.Hour},
+	}
+
+	rows := make(map[string]int)
+	for i, row := range ws.Rows {
+		if len(row) > 0 {
+			rows[row[0]] = i
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			i, ok := rows[tt.kind]
+			if !ok {
+				t.Fatalf("row %q not found", tt.kind)
+			}
+			if got := ws.Rows[i][1]; got != tt.text {
+				t.Errorf("text = %q, want %q", got, tt.text)
+			}
+			if got := ws.Values[i][1]; !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("value = %#v, want %#v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestIsDateFormatCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"yyyy-mm-dd", true},
+		{`yyyy\-mm\-dd hh:mm:ss.000`, true},
+		{"hh:mm:ss.0", true},
+		{"[h]:mm:ss", true},
+		{`[$-409]h:mm AM/PM`, true},
+		{`"Day "d`, true},
+		{"0.00", false},
+		{"#,##0", false},
+		{`0.0" s"`, false},
+		{"# ?/?", false},
+		{"@", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDateFormatCode(tt.code); got != tt.want {
+			t.Errorf("isDateFormatCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestDateStyleOf(t *testing.T) {
+	tests := []struct {
+		code string
+		want dateStyle
+	}{
+		{"yyyy-mm-dd", dateStyle{}},
+		{"yyyy-mm-dd hh:mm", dateStyle{withTime: true}},
+		{"hh:mm", dateStyle{withTime: true, timeOnly: true}},
+		{"mm:ss.0", dateStyle{withTime: true, timeOnly: true}},
+		{"[h]:mm", dateStyle{withTime: true, timeOnly: true, elapsed: true}},
+		{"[mm]:ss", dateStyle{withTime: true, timeOnly: true, elapsed: true}},
+	}
+
+	for _, tt := range tests {
+		if got := dateStyleOf(tt.code); got != tt.want {
+			t.Errorf("dateStyleOf(%q) = %+v, want %+v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// fakeCall is a request received by fakeSheets
+type fakeCall struct {
+	// method is the HTTP method
+	method string
+	// path is the unescaped URL path after the spreadsheet ID, e.g. "/values/'Sheet1'!A3"
+	// or ":batchUpdate"
+	path string
+	// body is the decoded JSON body
+	body map[string]interface{}
+}
+
+// fakeSheets is a fake Sheets API server holding a single sheet
+type fakeSheets struct {
+	// props is the properties of the sheet
+	props *sheets.SheetProperties
+	// values is the current values of the sheet, returned for every values read
+	values [][]interface{}
+	// calls records the requests received
+	calls []fakeCall
+}
+
+// newFakeClient starts a fake Sheets API server for the sheet and returns a client using it
+func newFakeClient(t *testing.T, fake *fakeSheets) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+
+	service, err := sheets.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{service: service, valueInputOption: ValueInputRaw, formulaPolicy: FormulaEscape}
+}
+
+// serve records the request and answers it
+func (f *fakeSheets) serve(w http.ResponseWriter, r *http.Request) {
+	path, _ := url.PathUnescape(r.URL.EscapedPath())
+	path = strings.TrimPrefix(path, "/v4/spreadsheets/test")
+
+	call := fakeCall{method: r.Method, path: path}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		json.Unmarshal(data, &call.body)
+	}
+	f.calls = append(f.calls, call)
+
+	var resp interface{} = map[string]interface{}{}
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		resp = &sheets.Spreadsheet{Sheets: []*sheets.Sheet{{Properties: f.props}}}
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/values/"):
+		resp = &sheets.ValueRange{Values: f.values}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// find returns the requests whose path starts with prefix
+func (f *fakeSheets) find(method, prefix string) []fakeCall {
+	var found []fakeCall
+	for _, call := range f.calls {
+		if call.method == method && strings.HasPrefix(call.path, prefix) {
+			found = append(found, call)
+		}
+	}
+	return found
+}
+
+// batchRequests returns the kinds of the requests sent with BatchUpdate, e.g. "appendDimension"
+func (f *fakeSheets) batchRequests() []string {
+	var kinds []string
+	for _, call := range f.find(http.MethodPost, ":batchUpdate") {
+		requests, _ := call.body["requests"].([]interface{})
+		for _, request := range requests {
+			for kind := range request.(map[string]interface{}) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	return kinds
+}
+
+// testProps returns the properties of a sheet named "Sheet1" with the given grid size
+func testProps(rows, cols int64) *sheets.SheetProperties {
+	return &sheets.SheetProperties{
+		SheetId:        7,
+		Title:          "Sheet1",
+		GridProperties: &sheets.GridProperties{RowCount: rows, ColumnCount: cols},
+	}
+}
+
+// testValues converts rows of strings to API values
+func testValues(rows ...[]string) [][]interface{} {
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row))
+		for j, cell := range row {
+			values[i][j] = cell
+		}
+	}
+	return values
+}
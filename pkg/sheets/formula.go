@@ -0,0 +1,98 @@
+package sheets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Policies for cells that Google Sheets would read as formulas, such as "=IMPORTXML(...)"
+const (
+	// FormulaEscape writes such cells as text by prefixing them with an apostrophe when
+	// the values are parsed (ValueInputUserEntered); raw values are always text
+	FormulaEscape = "escape"
+	// FormulaAllow writes such cells as they are
+	FormulaAllow = "allow"
+	// FormulaReject refuses data that contains such cells
+	FormulaReject = "reject"
+)
+
+// ErrFormula is returned when the data contains a formula with FormulaReject
+var ErrFormula = errors.New("data contains a formula")
+
+// signedNumberPattern matches numbers with a leading sign, which are not formulas
+var signedNumberPattern = regexp.MustCompile(`^[+-][0-9.,]*[0-9][0-9.,]*([eE][+-]?[0-9]+)?%?$`)
+
+// signedExpressionPattern matches cells where the leading signs are followed by
+// something Google Sheets would evaluate: a name, number, reference, string or group
+var signedExpressionPattern = regexp.MustCompile(`^[+-][+\-\s]*[\pL0-9.$"(]`)
+
+// IsFormula reports whether the cell would be read as a formula: it starts with "="
+// or "@", or with "+" or "-" followed by an expression. Signed numbers such as "-5"
+// or "+1.5" and placeholders such as "-" or "--" are not formulas.
+func IsFormula(cell string) bool {
+	if cell == "" {
+		return false
+	}
+	switch cell[0] {
+	case '=', '@':
+		return true
+	case '+', '-':
+		return signedExpressionPattern.MatchString(cell) && !signedNumberPattern.MatchString(cell)
+	}
+	return false
+}
+
+// EscapeFormula prefixes the cell with an apostrophe if it would be read as a formula,
+// so that Google Sheets keeps it as text
+func EscapeFormula(cell string) string {
+	if IsFormula(cell) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// CheckFormulas returns ErrFormula with the position of the first cell that would be
+// read as a formula
+func CheckFormulas(data [][]string) error {
+	return checkFormulas(data, 0)
+}
+
+// checkFormulas is CheckFormulas for rows that start after offset rows of the sheet
+func checkFormulas(data [][]string, offset int) error {
+	for r, row := range data {
+		for col, cell := range row {
+			if IsFormula(cell) {
+				return fmt.Errorf("%w: cell %s%d is %q", ErrFormula, columnName(col), offset+r+1, cell)
+			}
+		}
+	}
+	return nil
+}
+
+// SetFormulaPolicy sets how the client writes cells that would be read as formulas
+// (FormulaEscape, FormulaAllow or FormulaReject). With FormulaReject, callers check
+// the data with CheckFormulas before writing; streamed rows are checked chunk by chunk.
+func (c *Client) SetFormulaPolicy(policy string) {
+	c.formulaPolicy = policy
+}
+
+// leadingZeroPattern matches numbers with a leading zero such as postal codes and
+// employee IDs, which lose the zero when parsed as numbers
+var leadingZeroPattern = regexp.MustCompile(`^0[0-9]+$`)
+
+// cellValue returns the value sent to the API for a string cell. When the values are
+// parsed, formulas are escaped according to the formula policy and numbers with a
+// leading zero are always escaped, so that they stay text.
+func (c *Client) cellValue(cell string) interface{} {
+	if c.valueInputOption != ValueInputUserEntered {
+		return cell
+	}
+	if leadingZeroPattern.MatchString(cell) {
+		return "'" + cell
+	}
+	if c.formulaPolicy == FormulaEscape {
+		return EscapeFormula(cell)
+	}
+	return cell
+}
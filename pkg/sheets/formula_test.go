@@ -0,0 +1,90 @@
+package sheets
+
+import "testing"
+
+func TestCellValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		valueInput string
+		policy     string
+		cell       string
+		want       interface{}
+	}{
+		{"raw keeps formulas", ValueInputRaw, FormulaEscape, "=1+2", "=1+2"},
+		{"raw keeps leading zeros", ValueInputRaw, FormulaEscape, "00123", "00123"},
+		{"user-entered escapes formulas", ValueInputUserEntered, FormulaEscape, "=1+2", "'=1+2"},
+		{"user-entered allows formulas", ValueInputUserEntered, FormulaAllow, "=1+2", "=1+2"},
+		{"user-entered escapes leading zeros", ValueInputUserEntered, FormulaAllow, "00123", "'00123"},
+		{"user-entered keeps numbers", ValueInputUserEntered, FormulaEscape, "0.5", "0.5"},
+		{"user-entered keeps zero", ValueInputUserEntered, FormulaEscape, "0", "0"},
+		{"user-entered keeps text", ValueInputUserEntered, FormulaEscape, "abc", "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{valueInputOption: tt.valueInput, formulaPolicy: tt.policy}
+			if got := c.cellValue(tt.cell); got != tt.want {
+				t.Errorf("cellValue(%q) = %v, want %v", tt.cell, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFormula(t *testing.T) {
+	tests := []struct {
+		cell string
+		want bool
+	}{
+		{"", false},
+		{"abc", false},
+		{"=1+2", true},
+		{"=IMPORTXML(A1)", true},
+		{"@SUM(A1:A2)", true},
+		{"+SUM(A1:A2)", true},
+		{"-A1", true},
+		{"-cmd|' /C calc'!A0", true},
+		{"+(1)", true},
+		{`-"text"`, true},
+		{"- 1+2", true},
+		{"--1+A1", true},
+		{"-rw-r--r--", true},
+		{"-5", false},
+		{"+1.5", false},
+		{"-1,234.5", false},
+		{"-1e10", false},
+		{"+3%", false},
+		{"-", false},
+		{"+", false},
+		{"--", false},
+		{"---", false},
+		{"- ", false},
+		{"-/-", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsFormula(tt.cell); got != tt.want {
+			t.Errorf("IsFormula(%q) = %v, want %v", tt.cell, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"=HYPERLINK(\"http://example.com\")", "'=HYPERLINK(\"http://example.com\")"},
+		{"@A1", "'@A1"},
+		{"-A1", "'-A1"},
+		{"-5", "-5"},
+		{"-", "-"},
+		{"text", "text"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeFormula(tt.cell); got != tt.want {
+			t.Errorf("EscapeFormula(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
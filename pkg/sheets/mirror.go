@@ -0,0 +1,143 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// MirrorResult summarizes the changes made by MirrorToSpreadsheet
+type MirrorResult struct {
+	// URL is the URL of the target sheet
+	URL string
+	// Updated is the number of existing rows that were rewritten
+	Updated int
+	// Appended is the number of rows added after the existing rows
+	Appended int
+	// Deleted is the number of stale rows deleted from the end of the sheet
+	Deleted int
+}
+
+// MirrorToSpreadsheet makes the target sheet exactly match data. Changed rows are rewritten
+// (clearing cells beyond the new row width), missing rows are added and rows beyond the end
+// of data are deleted. Unchanged rows are not rewritten. If sheetName is empty, the first
+// sheet is used. If the named sheet does not exist yet, it is created.
+func (c *Client) MirrorToSpreadsheet(ctx context.Context, spreadsheetID, sheetName string, data [][]string, opts SheetOptions) (*MirrorResult, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data provided")
+	}
+
+	existing, err := c.getSheetProperties(ctx, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := c.targetSheet(ctx, spreadsheetID, existing, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.readValues(ctx, spreadsheetID, props.Title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet values: %w", err)
+	}
+
+	result := &MirrorResult{URL: sheetURL(spreadsheetID, props.SheetId)}
+
+	// Make sure the grid is large enough for the new data
+	if err := c.ensureGridSize(ctx, spreadsheetID, props, len(data), maxRowWidth(data)); err != nil {
+		return nil, fmt.Errorf("failed to resize sheet: %w", err)
+	}
+
+	// Rewrite changed rows, padding them to clear stale trailing cells
+	updates := make(map[int][]string)
+	for i := 0; i < len(data) && i < len(current); i++ {
+		if !rowsEqual(current[i], data[i]) {
+			updates[i] = padRow(data[i], len(current[i]))
+		}
+	}
+	if err := c.updateRows(ctx, spreadsheetID, props.Title, updates); err != nil {
+		return nil, fmt.Errorf("failed to update rows: %w", err)
+	}
+	result.Updated = len(updates)
+
+	// Write rows beyond the current end of the sheet
+	if len(data) > len(current) {
+		newRows := data[len(current):]
+		rangeStr := a1Range(props.Title, fmt.Sprintf("A%d", len(current)+1))
+		if err := c.writeRange(ctx, spreadsheetID, rangeStr, newRows); err != nil {
+			return nil, fmt.Errorf("failed to write data: %w", err)
+		}
+		result.Appended = len(newRows)
+	}
+
+	// Delete stale rows that no longer exist in the input
+	if len(current) > len(data) {
+		if err := c.removeRows(ctx, spreadsheetID, props, len(data), len(current)); err != nil {
+			return nil, fmt.Errorf("failed to delete rows: %w", err)
+		}
+		result.Deleted = len(current) - len(data)
+	}
+
+	if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, len(data), len(data[0]), opts); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// removeRows deletes the 0-indexed rows [startRow, endRow) of the sheet. The API refuses
+// to delete rows when only frozen rows would be left, so in that case the rows are
+// cleared instead.
+func (c *Client) removeRows(ctx context.Context, spreadsheetID string, props *sheets.SheetProperties, startRow, endRow int) error {
+	rowCount, frozenRows := int64(endRow), int64(0)
+	if grid := props.GridProperties; grid != nil {
+		rowCount = max(grid.RowCount, rowCount)
+		frozenRows = grid.FrozenRowCount
+	}
+	if rowCount-int64(endRow-startRow) > frozenRows {
+		return c.deleteRows(ctx, spreadsheetID, props.SheetId, startRow, endRow)
+	}
+
+	_, err := c.service.Spreadsheets.Values.Clear(
+		spreadsheetID,
+		a1Range(props.Title, fmt.Sprintf("%d:%d", startRow+1, endRow)),
+		&sheets.ClearValuesRequest{},
+	).Context(ctx).Do()
+	return err
+}
+
+// deleteRows deletes the 0-indexed rows [startRow, endRow) of the sheet
+func (c *Client) deleteRows(ctx context.Context, spreadsheetID string, sheetID int64, startRow, endRow int) error {
+	requests := []*sheets.Request{
+		{
+			DeleteDimension: &sheets.DeleteDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    sheetID,
+					Dimension:  "ROWS",
+					StartIndex: int64(startRow),
+					EndIndex:   int64(endRow),
+				},
+			},
+		},
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	return err
+}
+
+// maxRowWidth returns the number of cells in the widest row
+func maxRowWidth(data [][]string) int {
+	width := 0
+	for _, row := range data {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
@@ -0,0 +1,66 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMirrorToSpreadsheetStaleRows(t *testing.T) {
+	current := testValues([]string{"id", "name"}, []string{"1", "a"}, []string{"2", "b"})
+	data := [][]string{{"id", "name"}}
+
+	tests := []struct {
+		name        string
+		rows        int64
+		frozenRows  int64
+		wantBatch   []string
+		wantCleared []string
+	}{
+		{
+			name:      "stale rows are deleted",
+			rows:      1000,
+			wantBatch: []string{"deleteDimension"},
+		},
+		{
+			name:       "deleted with frozen rows left over",
+			rows:       1000,
+			frozenRows: 1,
+			wantBatch:  []string{"deleteDimension"},
+		},
+		{
+			name:        "cleared when only frozen rows would be left",
+			rows:        3,
+			frozenRows:  1,
+			wantCleared: []string{"/values/'Sheet1'!2:3:clear"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := testProps(tt.rows, 26)
+			props.GridProperties.FrozenRowCount = tt.frozenRows
+			fake := &fakeSheets{props: props, values: current}
+			c := newFakeClient(t, fake)
+
+			result, err := c.MirrorToSpreadsheet(context.Background(), "test", "", data, SheetOptions{})
+			if err != nil {
+				t.Fatalf("MirrorToSpreadsheet() error = %v", err)
+			}
+			if result.Deleted != 2 {
+				t.Errorf("Deleted = %d, want 2", result.Deleted)
+			}
+			if got := fake.batchRequests(); !reflect.DeepEqual(got, tt.wantBatch) {
+				t.Errorf("batch requests = %v, want %v", got, tt.wantBatch)
+			}
+			var cleared []string
+			for _, call := range fake.find(http.MethodPost, "/values/") {
+				cleared = append(cleared, call.path)
+			}
+			if !reflect.DeepEqual(cleared, tt.wantCleared) {
+				t.Errorf("cleared = %v, want %v", cleared, tt.wantCleared)
+			}
+		})
+	}
+}
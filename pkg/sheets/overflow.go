@@ -0,0 +1,225 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Size limits of Google Sheets
+const (
+	// MaxCells is the maximum number of cells in a spreadsheet, counted over all sheets
+	MaxCells = 10000000
+	// MaxCellChars is the maximum number of characters in a cell
+	MaxCellChars = 50000
+)
+
+// Size of the grid of a new sheet, which counts toward MaxCells even when the data is smaller
+const (
+	defaultGridRows = 1000
+	defaultGridCols = 26
+)
+
+// Policies for data that exceeds the size limits of a spreadsheet
+const (
+	// OverflowFail returns an error before anything is created
+	OverflowFail = "fail"
+	// OverflowTruncate drops the rows that do not fit and shortens cells that are too long
+	OverflowTruncate = "truncate"
+	// OverflowSplitSheets continues the rows that do not fit in extra spreadsheets, on
+	// sheets numbered like "Sheet1 (2)"
+	OverflowSplitSheets = "split-sheets"
+	// OverflowSplitFiles continues the rows that do not fit in extra spreadsheets, on
+	// sheets with the same name. It differs from OverflowSplitSheets only in the names.
+	OverflowSplitFiles = "split-files"
+)
+
+// ErrTooLarge is returned when the data exceeds the size limits with OverflowFail
+var ErrTooLarge = errors.New("data exceeds the Google Sheets size limits")
+
+// OverflowResult is the result of CreateSpreadsheets
+type OverflowResult struct {
+	// URLs is the URLs of the spreadsheets created
+	URLs []string
+	// TruncatedCells is the number of cells shortened to MaxCellChars characters
+	TruncatedCells int
+	// DroppedRows is the number of rows left out by OverflowTruncate
+	DroppedRows int
+}
+
+// CreateSpreadsheets creates one or more new spreadsheets with the given sheets. The size
+// limits are checked before anything is uploaded, and data that exceeds them is handled
+// according to overflow (OverflowFail, OverflowTruncate, OverflowSplitSheets or
+// OverflowSplitFiles). Rows are only continued once a spreadsheet is full, so both split
+// policies continue in extra spreadsheets, titled "title (2)", "title (3)" and so on.
+// Continued sheets repeat the header row.
+func (c *Client) CreateSpreadsheets(ctx context.Context, title string, sheetList []Sheet, overflow string) (*OverflowResult, error) {
+	plan, result, err := planSpreadsheets(sheetList, overflow)
+	if err != nil {
+		return nil, err
+	}
+
+	// If no title is provided, generate one from timestamp
+	if title == "" {
+		title = generateDefaultTitle()
+	}
+	for i, parts := range plan {
+		partTitle := title
+		if i > 0 {
+			partTitle = fmt.Sprintf("%s (%d)", title, i+1)
+		}
+		url, err := c.createSpreadsheet(ctx, partTitle, parts)
+		if err != nil {
+			return nil, err
+		}
+		result.URLs = append(result.URLs, url)
+	}
+	return result, nil
+}
+
+// planSpreadsheets distributes the sheets over as few spreadsheets as the cell limit
+// allows and shortens cells over the character limit, according to overflow
+func planSpreadsheets(sheetList []Sheet, overflow string) ([][]Sheet, *OverflowResult, error) {
+	switch overflow {
+	case OverflowFail, OverflowTruncate, OverflowSplitSheets, OverflowSplitFiles:
+	default:
+		return nil, nil, fmt.Errorf("unknown overflow policy: %s (supported: %s, %s, %s, %s)", overflow, OverflowFail, OverflowTruncate, OverflowSplitSheets, OverflowSplitFiles)
+	}
+	result := &OverflowResult{}
+
+	// Check the length of every cell
+	for _, sheet := range sheetList {
+		for r, row := range sheet.Data {
+			for col, cell := range row {
+				n := utf8.RuneCountInString(cell)
+				if n <= MaxCellChars {
+					continue
+				}
+				if overflow == OverflowFail {
+					return nil, nil, fmt.Errorf("%w: cell %s%d of %s has %d characters, %d allowed", ErrTooLarge, columnName(col), r+1, sheetTitle(sheet), n, MaxCellChars)
+				}
+				truncateCell(sheet, r, col)
+				result.TruncatedCells++
+			}
+		}
+	}
+
+	// Check the number of cells
+	total := 0
+	for _, sheet := range sheetList {
+		total += gridCells(len(sheet.Data), maxRowWidth(sheet.Data))
+	}
+	if total <= MaxCells {
+		return [][]Sheet{sheetList}, result, nil
+	}
+	if overflow == OverflowFail {
+		return nil, nil, fmt.Errorf("%w: %d cells needed, %d allowed per spreadsheet", ErrTooLarge, total, MaxCells)
+	}
+
+	var plan [][]Sheet
+	var current []Sheet
+	remaining := MaxCells
+	for _, sheet := range sheetList {
+		width := max(maxRowWidth(sheet.Data), defaultGridCols)
+		if defaultGridRows*width > MaxCells {
+			return nil, nil, fmt.Errorf("%w: %s has %d columns, too many to fit in a spreadsheet", ErrTooLarge, sheetTitle(sheet), width)
+		}
+
+		start, part := 0, 1
+		for start < len(sheet.Data) {
+			// Continued parts repeat the header row
+			header := 0
+			if start > 0 {
+				header = 1
+			}
+			rows := len(sheet.Data) - start + header
+			fit := remaining / width
+			if gridCells(rows, width) > remaining && fit < defaultGridRows {
+				// No room left in this spreadsheet
+				if overflow == OverflowTruncate {
+					result.DroppedRows += len(sheet.Data) - start
+					break
+				}
+				plan = append(plan, current)
+				current, remaining = nil, MaxCells
+				continue
+			}
+
+			end := len(sheet.Data)
+			if gridCells(rows, width) > remaining {
+				end = start + fit - header
+			}
+			name := sheetTitle(sheet)
+			if part > 1 && overflow == OverflowSplitSheets {
+				name = numberedSheetName(name, part)
+			}
+			current = append(current, sheetPart(sheet, name, start, end, header == 1))
+			remaining -= gridCells(end-start+header, width)
+			start = end
+			part++
+		}
+	}
+	if len(current) > 0 {
+		plan = append(plan, current)
+	}
+	return plan, result, nil
+}
+
+// sheetPart returns the sheet with rows [start, end) of sheet, preceded by its header
+// row if withHeader is set
+func sheetPart(sheet Sheet, name string, start, end int, withHeader bool) Sheet {
+	part := Sheet{Name: name, Formats: sheet.Formats, Options: sheet.Options}
+	if withHeader {
+		part.Data = append([][]string{sheet.Data[0]}, sheet.Data[start:end]...)
+	} else {
+		part.Data = sheet.Data[start:end]
+	}
+	if sheet.Values != nil {
+		if withHeader {
+			part.Values = append([][]interface{}{sheet.Values[0]}, sheet.Values[start:end]...)
+		} else {
+			part.Values = sheet.Values[start:end]
+		}
+	}
+	return part
+}
+
+// truncateCell shortens the cell to MaxCellChars characters, in the typed values too
+func truncateCell(sheet Sheet, row, col int) {
+	cut := func(s string) string {
+		runes := []rune(s)
+		if len(runes) > MaxCellChars {
+			return string(runes[:MaxCellChars])
+		}
+		return s
+	}
+	sheet.Data[row][col] = cut(sheet.Data[row][col])
+	if sheet.Values != nil && row < len(sheet.Values) && col < len(sheet.Values[row]) {
+		if s, ok := sheet.Values[row][col].(string); ok {
+			sheet.Values[row][col] = cut(s)
+		}
+	}
+}
+
+// gridCells returns the number of cells of a sheet holding data of the given size
+func gridCells(rows, cols int) int {
+	return max(rows, defaultGridRows) * max(cols, defaultGridCols)
+}
+
+// sheetTitle returns the name of the sheet, or DefaultSheetName if it has none
+func sheetTitle(sheet Sheet) string {
+	if sheet.Name == "" {
+		return DefaultSheetName
+	}
+	return sheet.Name
+}
+
+// columnName returns the A1 notation name of the 0-indexed column, e.g. "A" or "AB"
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
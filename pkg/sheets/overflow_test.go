@@ -0,0 +1,191 @@
+package sheets
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// testSheet returns a sheet with a header row followed by rows-1 data rows of cols
+// columns. The data rows share one slice to keep large sheets cheap.
+func testSheet(name string, rows, cols int) Sheet {
+	header := make([]string, cols)
+	row := make([]string, cols)
+	for i := range header {
+		header[i] = columnName(i)
+		row[i] = "x"
+	}
+	data := make([][]string, rows)
+	data[0] = header
+	for i := 1; i < rows; i++ {
+		data[i] = row
+	}
+	return Sheet{Name: name, Data: data}
+}
+
+// planShape describes each spreadsheet of a plan as "name:rows" for each of its sheets
+func planShape(plan [][]Sheet) [][]string {
+	shape := make([][]string, len(plan))
+	for i, parts := range plan {
+		for _, part := range parts {
+			shape[i] = append(shape[i], fmt.Sprintf("%s:%d", part.Name, len(part.Data)))
+		}
+	}
+	return shape
+}
+
+func TestPlanSpreadsheets(t *testing.T) {
+	// 100 columns x 100,000 rows is exactly MaxCells
+	const cols, fullRows = 100, MaxCells / 100
+
+	tests := []struct {
+		name        string
+		sheets      []Sheet
+		overflow    string
+		want        [][]string
+		wantDropped int
+		wantErr     error
+	}{
+		{
+			name:     "exactly the cell limit",
+			sheets:   []Sheet{testSheet("Data", fullRows, cols)},
+			overflow: OverflowFail,
+			want:     [][]string{{"Data:100000"}},
+		},
+		{
+			name:     "exactly the cell limit over two sheets",
+			sheets:   []Sheet{testSheet("A", fullRows/2, cols), testSheet("B", fullRows/2, cols)},
+			overflow: OverflowFail,
+			want:     [][]string{{"A:50000", "B:50000"}},
+		},
+		{
+			name:     "one row over the limit fails",
+			sheets:   []Sheet{testSheet("Data", fullRows+1, cols)},
+			overflow: OverflowFail,
+			wantErr:  ErrTooLarge,
+		},
+		{
+			name:     "split sheets",
+			sheets:   []Sheet{testSheet("Data", fullRows+1, cols)},
+			overflow: OverflowSplitSheets,
+			want:     [][]string{{"Data:100000"}, {"Data (2):2"}},
+		},
+		{
+			name:     "split sheets fills the rest of the spreadsheet",
+			sheets:   []Sheet{testSheet("A", fullRows/2, cols), testSheet("B", fullRows/2+1, cols)},
+			overflow: OverflowSplitSheets,
+			want:     [][]string{{"A:50000", "B:50000"}, {"B (2):2"}},
+		},
+		{
+			name:     "split files",
+			sheets:   []Sheet{testSheet("Data", fullRows+1, cols)},
+			overflow: OverflowSplitFiles,
+			want:     [][]string{{"Data:100000"}, {"Data:2"}},
+		},
+		{
+			name:     "split files places sheets like split sheets",
+			sheets:   []Sheet{testSheet("A", fullRows/2, cols), testSheet("B", fullRows/2+1, cols)},
+			overflow: OverflowSplitFiles,
+			want:     [][]string{{"A:50000", "B:50000"}, {"B:2"}},
+		},
+		{
+			name:        "truncate",
+			sheets:      []Sheet{testSheet("Data", fullRows+1, cols)},
+			overflow:    OverflowTruncate,
+			want:        [][]string{{"Data:100000"}},
+			wantDropped: 1,
+		},
+		{
+			name:        "truncate drops later sheets",
+			sheets:      []Sheet{testSheet("A", fullRows, cols), testSheet("B", 10, cols)},
+			overflow:    OverflowTruncate,
+			want:        [][]string{{"A:100000"}},
+			wantDropped: 10,
+		},
+		{
+			name:     "too many columns",
+			sheets:   []Sheet{testSheet("Wide", 2, MaxCells/defaultGridRows+1)},
+			overflow: OverflowSplitSheets,
+			wantErr:  ErrTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, result, err := planSpreadsheets(tt.sheets, tt.overflow)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("planSpreadsheets() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planSpreadsheets() error = %v", err)
+			}
+			if got := planShape(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planSpreadsheets() = %v, want %v", got, tt.want)
+			}
+			if result.DroppedRows != tt.wantDropped {
+				t.Errorf("DroppedRows = %d, want %d", result.DroppedRows, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestPlanSpreadsheetsRepeatsHeader(t *testing.T) {
+	sheet := testSheet("Data", MaxCells/100+1, 100)
+	sheet.Values = make([][]interface{}, len(sheet.Data))
+	sheet.Values[0] = []interface{}{"header"}
+	sheet.Values[len(sheet.Values)-1] = []interface{}{1.5}
+
+	plan, _, err := planSpreadsheets([]Sheet{sheet}, OverflowSplitSheets)
+	if err != nil {
+		t.Fatalf("planSpreadsheets() error = %v", err)
+	}
+	last := plan[len(plan)-1][0]
+	if !reflect.DeepEqual(last.Data[0], sheet.Data[0]) {
+		t.Errorf("continued sheet starts with %q, want the header row", last.Data[0][:3])
+	}
+	want := [][]interface{}{{"header"}, {1.5}}
+	if !reflect.DeepEqual(last.Values, want) {
+		t.Errorf("continued sheet values = %v, want %v", last.Values, want)
+	}
+}
+
+func TestPlanSpreadsheetsLongCells(t *testing.T) {
+	long := strings.Repeat("あ", MaxCellChars+1)
+	newSheet := func() Sheet {
+		return Sheet{
+			Data:   [][]string{{"name"}, {long}},
+			Values: [][]interface{}{{"name"}, {long}},
+		}
+	}
+
+	if _, _, err := planSpreadsheets([]Sheet{newSheet()}, OverflowFail); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("planSpreadsheets() error = %v, want %v", err, ErrTooLarge)
+	}
+
+	sheet := newSheet()
+	_, result, err := planSpreadsheets([]Sheet{sheet}, OverflowTruncate)
+	if err != nil {
+		t.Fatalf("planSpreadsheets() error = %v", err)
+	}
+	if result.TruncatedCells != 1 {
+		t.Errorf("TruncatedCells = %d, want 1", result.TruncatedCells)
+	}
+	if n := utf8.RuneCountInString(sheet.Data[1][0]); n != MaxCellChars {
+		t.Errorf("truncated cell has %d characters, want %d", n, MaxCellChars)
+	}
+	if n := utf8.RuneCountInString(sheet.Values[1][0].(string)); n != MaxCellChars {
+		t.Errorf("truncated value has %d characters, want %d", n, MaxCellChars)
+	}
+}
+
+func TestPlanSpreadsheetsUnknownPolicy(t *testing.T) {
+	if _, _, err := planSpreadsheets([]Sheet{testSheet("Data", 1, 1)}, "drop"); err == nil {
+		t.Error("planSpreadsheets() error = nil, want an error")
+	}
+}
@@ -3,6 +3,8 @@ package sheets
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -13,8 +15,21 @@ import (
 // Client wraps the Google Sheets API client
 type Client struct {
 	service *sheets.Service
+	// valueInputOption is how the values written are interpreted (ValueInputRaw or ValueInputUserEntered)
+	valueInputOption string
+	// formulaPolicy is how cells that would be read as formulas are written
+	formulaPolicy string
 }
 
+// How written values are interpreted by Google Sheets
+const (
+	// ValueInputRaw stores the values as they are, e.g. "=1+2" stays text
+	ValueInputRaw = "RAW"
+	// ValueInputUserEntered parses the values as if typed into the UI, so that numbers,
+	// dates and formulas are recognized
+	ValueInputUserEntered = "USER_ENTERED"
+)
+
 // NewClient creates a new Sheets client
 func NewClient(ctx context.Context, config *oauth2.Config, token *oauth2.Token) (*Client, error) {
 	httpClient := config.Client(ctx, token)
@@ -24,11 +39,78 @@ func NewClient(ctx context.Context, config *oauth2.Config, token *oauth2.Token)
 		return nil, fmt.Errorf("failed to create sheets service: %w", err)
 	}
 
-	return &Client{service: service}, nil
+	return &Client{service: service, valueInputOption: ValueInputRaw, formulaPolicy: FormulaEscape}, nil
+}
+
+// SetValueInputOption sets how the values written by the client are interpreted
+// (ValueInputRaw or ValueInputUserEntered)
+func (c *Client) SetValueInputOption(option string) {
+	c.valueInputOption = option
+}
+
+// SheetOptions holds the display settings applied to a sheet after data is written
+type SheetOptions struct {
+	// FreezeRows is the number of rows to freeze (0 means no freeze)
+	FreezeRows int
+	// FreezeCols is the number of columns to freeze (0 means no freeze)
+	FreezeCols int
+	// FilterHeaderRow is the 1-indexed header row for basic filter (0 means no filter)
+	FilterHeaderRow int
+	// HeaderStyle is the format of the header row, which is FilterHeaderRow or else the
+	// first row (nil means no style)
+	HeaderStyle *HeaderStyle
+	// AutoResize fits the width of the columns to their content
+	AutoResize bool
+	// MaxColWidth is the maximum width in pixels of auto-resized columns (0 means no limit)
+	MaxColWidth int
+}
+
+// Policies for adding a sheet whose name already exists in the spreadsheet
+const (
+	// IfExistsFail returns an error
+	IfExistsFail = "fail"
+	// IfExistsOverwrite clears the existing sheet and writes the data there
+	IfExistsOverwrite = "overwrite"
+	// IfExistsSuffix adds a new sheet whose name is suffixed with a counter, e.g. "name (2)"
+	IfExistsSuffix = "suffix"
+)
+
+// DefaultSheetName is the name of the sheet created in a new spreadsheet
+const DefaultSheetName = "Sheet1"
+
+// spreadsheetIDPattern matches the spreadsheet ID in a Google Sheets URL
+var spreadsheetIDPattern = regexp.MustCompile(`/spreadsheets/d/([a-zA-Z0-9_-]+)`)
+
+// ParseSpreadsheetID extracts the spreadsheet ID from a spreadsheet URL or returns the ID as-is
+func ParseSpreadsheetID(idOrURL string) (string, error) {
+	idOrURL = strings.TrimSpace(idOrURL)
+	if m := spreadsheetIDPattern.FindStringSubmatch(idOrURL); m != nil {
+		return m[1], nil
+	}
+	if idOrURL == "" || strings.ContainsAny(idOrURL, "/?#: ") {
+		return "", fmt.Errorf("invalid spreadsheet ID or URL: %q", idOrURL)
+	}
+	return idOrURL, nil
+}
+
+// Sheet is a sheet (tab) to be created with its data and display settings
+type Sheet struct {
+	// Name is the sheet name (DefaultSheetName is used if empty)
+	Name string
+	// Data is the rows to write to the sheet
+	Data [][]string
+	// Values optionally holds typed cell values (string, float64, bool, time.Time, time.Duration or nil)
+	// that are written instead of Data, so that numbers and dates are not written as text
+	Values [][]interface{}
+	// Formats holds the number formats applied to columns after data is written
+	Formats []ColumnFormat
+	// Options holds the display settings applied after data is written
+	Options SheetOptions
 }
 
-// CreateSpreadsheet creates a new spreadsheet with the given title and data
-func (c *Client) CreateSpreadsheet(ctx context.Context, title string, data [][]string, freezeRows, freezeCols, filterHeaderRow int) (string, error) {
+// createSpreadsheet creates a new spreadsheet with the given title and one sheet per
+// element of sheetList without checking the size limits
+func (c *Client) createSpreadsheet(ctx context.Context, title string, sheetList []Sheet) (string, error) {
 	// If no title is provided, generate one from timestamp
 	if title == "" {
 		title = generateDefaultTitle()
@@ -39,13 +121,21 @@ func (c *Client) CreateSpreadsheet(ctx context.Context, title string, data [][]s
 		Properties: &sheets.SpreadsheetProperties{
 			Title: title,
 		},
-		Sheets: []*sheets.Sheet{
-			{
-				Properties: &sheets.SheetProperties{
-					Title: "Sheet1",
-				},
-			},
-		},
+	}
+	var names []*sheets.SheetProperties
+	for _, sheet := range sheetList {
+		name := sheet.Name
+		if name == "" {
+			name = DefaultSheetName
+		}
+		if findSheet(names, name) != nil {
+			name = uniqueSheetName(names, name)
+		}
+		props := &sheets.SheetProperties{
+			Title: name,
+		}
+		names = append(names, props)
+		spreadsheet.Sheets = append(spreadsheet.Sheets, &sheets.Sheet{Properties: props})
 	}
 
 	resp, err := c.service.Spreadsheets.Create(spreadsheet).Context(ctx).Do()
@@ -54,56 +144,336 @@ func (c *Client) CreateSpreadsheet(ctx context.Context, title string, data [][]s
 	}
 
 	spreadsheetID := resp.SpreadsheetId
-	sheetID := resp.Sheets[0].Properties.SheetId
 
-	// Write data to the spreadsheet
+	for i, sheet := range sheetList {
+		if len(sheet.Data) == 0 {
+			continue
+		}
+		sheetName := names[i].Title
+		sheetID := resp.Sheets[i].Properties.SheetId
+
+		// Write data to the spreadsheet
+		if err := c.writeSheet(ctx, spreadsheetID, sheetName, sheetID, sheet); err != nil {
+			return "", fmt.Errorf("failed to write data to %s: %w", sheetName, err)
+		}
+
+		// Apply freeze panes, basic filter and header style
+		if err := c.applySheetOptions(ctx, spreadsheetID, sheetID, len(sheet.Data), len(sheet.Data[0]), sheet.Options); err != nil {
+			return "", err
+		}
+	}
+
+	// Return the spreadsheet URL
+	url := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit", spreadsheetID)
+	return url, nil
+}
+
+// AppendToSpreadsheet appends data after the last non-empty row of the first sheet
+// of an existing spreadsheet, starting at column A. Blank rows and side tables do not
+// move the position. If the first row of data is identical to the sheet's current
+// header row, it is skipped so that repeated runs do not duplicate the header.
+func (c *Client) AppendToSpreadsheet(ctx context.Context, spreadsheetID string, data [][]string, opts SheetOptions) (string, error) {
+	existing, err := c.getSheetProperties(ctx, spreadsheetID)
+	if err != nil {
+		return "", err
+	}
+	if len(existing) == 0 {
+		return "", fmt.Errorf("spreadsheet %s has no sheets", spreadsheetID)
+	}
+	props := existing[0]
+
+	current, err := c.readValues(ctx, spreadsheetID, props.Title)
+	if err != nil {
+		return "", fmt.Errorf("failed to read sheet values: %w", err)
+	}
+
+	// Skip the header row if the sheet already starts with the same header
+	var header []string
+	if len(current) > 0 {
+		header = current[0]
+	}
+	if len(header) > 0 && len(data) > 0 && rowsEqual(header, data[0]) {
+		data = data[1:]
+	}
+
+	lastRow := len(current)
 	if len(data) > 0 {
-		if err := c.writeData(ctx, spreadsheetID, "Sheet1", data); err != nil {
+		lastRow, err = c.writeRowsAfter(ctx, spreadsheetID, props, len(current), data)
+		if err != nil {
+			return "", fmt.Errorf("failed to append data: %w", err)
+		}
+	}
+
+	// Apply freeze panes, basic filter and header style over the whole data range
+	numCols := len(header)
+	if len(data) > 0 && len(data[0]) > numCols {
+		numCols = len(data[0])
+	}
+	if lastRow > 0 && numCols > 0 {
+		if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, lastRow, numCols, opts); err != nil {
+			return "", err
+		}
+	}
+
+	return sheetURL(spreadsheetID, props.SheetId), nil
+}
+
+// AddSheetToSpreadsheet adds a new sheet to an existing spreadsheet and writes its data.
+// ifExists decides what happens when a sheet with the same name already exists
+// (IfExistsFail, IfExistsOverwrite or IfExistsSuffix).
+func (c *Client) AddSheetToSpreadsheet(ctx context.Context, spreadsheetID string, sheet Sheet, ifExists string) (string, error) {
+	existing, err := c.getSheetProperties(ctx, spreadsheetID)
+	if err != nil {
+		return "", err
+	}
+
+	sheetName := sheet.Name
+	if sheetName == "" {
+		sheetName = DefaultSheetName
+	}
+
+	var sheetID int64
+	if props := findSheet(existing, sheetName); props != nil {
+		switch ifExists {
+		case IfExistsFail:
+			return "", fmt.Errorf("sheet %q already exists in spreadsheet %s", props.Title, spreadsheetID)
+		case IfExistsOverwrite:
+			if err := c.clearSheet(ctx, spreadsheetID, props.Title); err != nil {
+				return "", fmt.Errorf("failed to clear sheet: %w", err)
+			}
+			sheetName = props.Title
+			sheetID = props.SheetId
+		case IfExistsSuffix:
+			sheetName = uniqueSheetName(existing, sheetName)
+			added, err := c.addSheet(ctx, spreadsheetID, sheetName)
+			if err != nil {
+				return "", fmt.Errorf("failed to add sheet: %w", err)
+			}
+			sheetID = added.SheetId
+		default:
+			return "", fmt.Errorf("unknown if-exists policy: %s (supported: %s, %s, %s)", ifExists, IfExistsFail, IfExistsOverwrite, IfExistsSuffix)
+		}
+	} else {
+		added, err := c.addSheet(ctx, spreadsheetID, sheetName)
+		if err != nil {
+			return "", fmt.Errorf("failed to add sheet: %w", err)
+		}
+		sheetID = added.SheetId
+	}
+
+	// Write data to the sheet
+	if data := sheet.Data; len(data) > 0 {
+		if err := c.writeSheet(ctx, spreadsheetID, sheetName, sheetID, sheet); err != nil {
 			return "", fmt.Errorf("failed to write data: %w", err)
 		}
+		if err := c.applySheetOptions(ctx, spreadsheetID, sheetID, len(data), len(data[0]), sheet.Options); err != nil {
+			return "", err
+		}
+	}
+
+	return sheetURL(spreadsheetID, sheetID), nil
+}
+
+// getSheetProperties returns the properties of all sheets in the spreadsheet
+func (c *Client) getSheetProperties(ctx context.Context, spreadsheetID string) ([]*sheets.SheetProperties, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Fields("sheets.properties").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	props := make([]*sheets.SheetProperties, 0, len(spreadsheet.Sheets))
+	for _, sheet := range spreadsheet.Sheets {
+		props = append(props, sheet.Properties)
+	}
+	return props, nil
+}
+
+// addSheet adds an empty sheet with the given name at the end and returns its properties
+func (c *Client) addSheet(ctx context.Context, spreadsheetID, sheetName string) (*sheets.SheetProperties, error) {
+	return c.addSheetAt(ctx, spreadsheetID, sheetName, -1)
+}
+
+// addSheetAt adds an empty sheet with the given name at the 0-indexed position
+// (a negative index adds it at the end) and returns its properties
+func (c *Client) addSheetAt(ctx context.Context, spreadsheetID, sheetName string, index int) (*sheets.SheetProperties, error) {
+	props := &sheets.SheetProperties{
+		Title: sheetName,
+	}
+	if index >= 0 {
+		props.Index = int64(index)
+		// Index 0 would be omitted from the request without this
+		props.ForceSendFields = []string{"Index"}
 	}
 
+	requests := []*sheets.Request{
+		{
+			AddSheet: &sheets.AddSheetRequest{
+				Properties: props,
+			},
+		},
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	resp, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Replies[0].AddSheet.Properties, nil
+}
+
+// ensureGridSize appends rows and columns to the sheet so that its grid has at least
+// numRows rows and numCols columns
+func (c *Client) ensureGridSize(ctx context.Context, spreadsheetID string, props *sheets.SheetProperties, numRows, numCols int) error {
+	var rowCount, colCount int64
+	if props.GridProperties != nil {
+		rowCount = props.GridProperties.RowCount
+		colCount = props.GridProperties.ColumnCount
+	} else {
+		props.GridProperties = &sheets.GridProperties{}
+	}
+
+	var requests []*sheets.Request
+	if int64(numRows) > rowCount {
+		requests = append(requests, &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   props.SheetId,
+				Dimension: "ROWS",
+				Length:    int64(numRows) - rowCount,
+			},
+		})
+	}
+	if int64(numCols) > colCount {
+		requests = append(requests, &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   props.SheetId,
+				Dimension: "COLUMNS",
+				Length:    int64(numCols) - colCount,
+			},
+		})
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	if int64(numRows) > rowCount {
+		props.GridProperties.RowCount = int64(numRows)
+	}
+	if int64(numCols) > colCount {
+		props.GridProperties.ColumnCount = int64(numCols)
+	}
+	return nil
+}
+
+// clearSheet clears all values in the specified sheet
+func (c *Client) clearSheet(ctx context.Context, spreadsheetID, sheetName string) error {
+	_, err := c.service.Spreadsheets.Values.Clear(
+		spreadsheetID,
+		quoteSheetName(sheetName),
+		&sheets.ClearValuesRequest{},
+	).Context(ctx).Do()
+	return err
+}
+
+// applySheetOptions applies freeze panes, basic filter, header style and column auto-resize
+// to the sheet in a single batch update. Auto-resized columns wider than MaxColWidth are
+// narrowed afterwards.
+func (c *Client) applySheetOptions(ctx context.Context, spreadsheetID string, sheetID int64, numRows, numCols int, opts SheetOptions) error {
+	var requests []*sheets.Request
+
 	// Apply freeze panes if specified
-	if freezeRows > 0 || freezeCols > 0 {
-		if err := c.setFreezePanes(ctx, spreadsheetID, sheetID, freezeRows, freezeCols); err != nil {
-			return "", fmt.Errorf("failed to set freeze panes: %w", err)
+	if opts.FreezeRows > 0 || opts.FreezeCols > 0 {
+		requests = append(requests, freezePanesRequest(sheetID, opts.FreezeRows, opts.FreezeCols))
+	}
+
+	// The filter, header style and column resizing need at least one column; a sheet
+	// without columns would make them fail
+	if numCols > 0 {
+		// Apply basic filter if specified
+		if opts.FilterHeaderRow > 0 {
+			requests = append(requests, basicFilterRequest(sheetID, opts.FilterHeaderRow, numRows, numCols))
+		}
+
+		// Apply header style if specified, to the filter header row or the first row
+		if opts.HeaderStyle != nil {
+			headerRow := opts.FilterHeaderRow
+			if headerRow == 0 {
+				headerRow = 1
+			}
+			requests = append(requests, headerStyleRequest(sheetID, headerRow, numCols, *opts.HeaderStyle))
 		}
+
+		// Resize the columns last so that the header style is taken into account
+		if opts.AutoResize {
+			requests = append(requests, autoResizeRequest(sheetID, numCols))
+		}
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to apply sheet options: %w", err)
 	}
 
-	// Apply basic filter if specified
-	if filterHeaderRow > 0 {
-		if err := c.setBasicFilter(ctx, spreadsheetID, sheetID, filterHeaderRow, len(data), len(data[0])); err != nil {
-			return "", fmt.Errorf("failed to set basic filter: %w", err)
+	if opts.AutoResize && opts.MaxColWidth > 0 && numCols > 0 {
+		if err := c.limitColumnWidths(ctx, spreadsheetID, sheetID, numCols, opts.MaxColWidth); err != nil {
+			return fmt.Errorf("failed to limit column widths: %w", err)
 		}
 	}
 
-	// Return the spreadsheet URL
-	url := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit", spreadsheetID)
-	return url, nil
+	return nil
 }
 
-// writeData writes data to the specified sheet
+// writeData writes data to the specified sheet, split into chunks so that a large
+// table does not exceed the request size limit
 func (c *Client) writeData(ctx context.Context, spreadsheetID, sheetName string, data [][]string) error {
-	// Convert [][]string to [][]interface{} for the API
-	var values [][]interface{}
-	for _, row := range data {
-		interfaceRow := make([]interface{}, len(row))
-		for i, cell := range row {
-			interfaceRow[i] = cell
+	chunks := newChunkReader(&sliceRows{data: data}, DefaultChunkRows, DefaultChunkBytes)
+	row := 1
+	for {
+		chunk, _, err := chunks.next()
+		if err != nil {
+			return err
 		}
-		values = append(values, interfaceRow)
+		if len(chunk) == 0 {
+			return nil
+		}
+		if err := c.writeRange(ctx, spreadsheetID, a1Range(sheetName, fmt.Sprintf("A%d", row)), chunk); err != nil {
+			return err
+		}
+		row += len(chunk)
 	}
+}
 
+// writeRange writes data starting at the top-left cell of the given A1 notation range
+func (c *Client) writeRange(ctx context.Context, spreadsheetID, rangeStr string, data [][]string) error {
 	valueRange := &sheets.ValueRange{
-		Values: values,
+		Values: c.toValues(data),
 	}
 
-	rangeStr := fmt.Sprintf("%s!A1", sheetName)
 	_, err := c.service.Spreadsheets.Values.Update(
 		spreadsheetID,
 		rangeStr,
 		valueRange,
-	).ValueInputOption("RAW").Context(ctx).Do()
+	).ValueInputOption(c.valueInputOption).Context(ctx).Do()
 
 	if err != nil {
 		return err
@@ -112,8 +482,23 @@ func (c *Client) writeData(ctx context.Context, spreadsheetID, sheetName string,
 	return nil
 }
 
-// setFreezePanes sets frozen rows and columns for the sheet
-func (c *Client) setFreezePanes(ctx context.Context, spreadsheetID string, sheetID int64, freezeRows, freezeCols int) error {
+// writeRowsAfter writes data from column A of the row after the 1-indexed row afterRow,
+// growing the grid if needed, and returns the 1-indexed number of the last row written.
+// The position does not depend on the tables the API would detect when appending.
+func (c *Client) writeRowsAfter(ctx context.Context, spreadsheetID string, props *sheets.SheetProperties, afterRow int, data [][]string) (int, error) {
+	lastRow := afterRow + len(data)
+	if err := c.ensureGridSize(ctx, spreadsheetID, props, lastRow, maxRowWidth(data)); err != nil {
+		return 0, fmt.Errorf("failed to resize sheet: %w", err)
+	}
+	rangeStr := a1Range(props.Title, fmt.Sprintf("A%d", afterRow+1))
+	if err := c.writeRange(ctx, spreadsheetID, rangeStr, data); err != nil {
+		return 0, err
+	}
+	return lastRow, nil
+}
+
+// freezePanesRequest returns the request that sets frozen rows and columns for the sheet
+func freezePanesRequest(sheetID int64, freezeRows, freezeCols int) *sheets.Request {
 	gridProperties := &sheets.GridProperties{}
 
 	if freezeRows > 0 {
@@ -124,32 +509,19 @@ func (c *Client) setFreezePanes(ctx context.Context, spreadsheetID string, sheet
 		gridProperties.FrozenColumnCount = int64(freezeCols)
 	}
 
-	requests := []*sheets.Request{
-		{
-			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
-				Properties: &sheets.SheetProperties{
-					SheetId:        sheetID,
-					GridProperties: gridProperties,
-				},
-				Fields: "gridProperties.frozenRowCount,gridProperties.frozenColumnCount",
+	return &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:        sheetID,
+				GridProperties: gridProperties,
 			},
+			Fields: "gridProperties.frozenRowCount,gridProperties.frozenColumnCount",
 		},
 	}
-
-	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: requests,
-	}
-
-	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// setBasicFilter sets a basic filter for the sheet
-func (c *Client) setBasicFilter(ctx context.Context, spreadsheetID string, sheetID int64, headerRow, numRows, numCols int) error {
+// basicFilterRequest returns the request that sets a basic filter for the sheet
+func basicFilterRequest(sheetID int64, headerRow, numRows, numCols int) *sheets.Request {
 	// Basic filter range starts from the header row (0-indexed)
 	// and spans all columns and rows from header to end
 	filterRange := &sheets.GridRange{
@@ -160,26 +532,71 @@ func (c *Client) setBasicFilter(ctx context.Context, spreadsheetID string, sheet
 		EndColumnIndex:   int64(numCols),
 	}
 
-	requests := []*sheets.Request{
-		{
-			SetBasicFilter: &sheets.SetBasicFilterRequest{
-				Filter: &sheets.BasicFilter{
-					Range: filterRange,
-				},
+	return &sheets.Request{
+		SetBasicFilter: &sheets.SetBasicFilterRequest{
+			Filter: &sheets.BasicFilter{
+				Range: filterRange,
 			},
 		},
 	}
+}
 
-	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: requests,
+// autoResizeRequest returns the request that fits the width of the columns to their content
+func autoResizeRequest(sheetID int64, numCols int) *sheets.Request {
+	return &sheets.Request{
+		AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+			Dimensions: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  "COLUMNS",
+				StartIndex: 0,
+				EndIndex:   int64(numCols),
+			},
+		},
 	}
+}
 
-	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+// limitColumnWidths narrows the columns of the sheet that are wider than maxWidth pixels
+func (c *Client) limitColumnWidths(ctx context.Context, spreadsheetID string, sheetID int64, numCols, maxWidth int) error {
+	resp, err := c.service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId),data(columnMetadata(pixelSize)))").
+		Context(ctx).Do()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	var requests []*sheets.Request
+	for _, sheet := range resp.Sheets {
+		if sheet.Properties == nil || sheet.Properties.SheetId != sheetID || len(sheet.Data) == 0 {
+			continue
+		}
+		for col, metadata := range sheet.Data[0].ColumnMetadata {
+			if col >= numCols || metadata.PixelSize <= int64(maxWidth) {
+				continue
+			}
+			requests = append(requests, &sheets.Request{
+				UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+					Range: &sheets.DimensionRange{
+						SheetId:    sheetID,
+						Dimension:  "COLUMNS",
+						StartIndex: int64(col),
+						EndIndex:   int64(col + 1),
+					},
+					Properties: &sheets.DimensionProperties{PixelSize: int64(maxWidth)},
+					Fields:     "pixelSize",
+				},
+			})
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	return err
 }
 
 // generateDefaultTitle generates a default title using the current timestamp
@@ -187,3 +604,90 @@ func generateDefaultTitle() string {
 	now := time.Now()
 	return now.Format("20060102150405") + "+gs"
 }
+
+// toValues converts [][]string to [][]interface{} for the API, escaping formulas
+// according to the formula policy
+func (c *Client) toValues(data [][]string) [][]interface{} {
+	var values [][]interface{}
+	for _, row := range data {
+		interfaceRow := make([]interface{}, len(row))
+		for i, cell := range row {
+			interfaceRow[i] = c.cellValue(cell)
+		}
+		values = append(values, interfaceRow)
+	}
+	return values
+}
+
+// quoteSheetName quotes a sheet name for use in A1 notation
+func quoteSheetName(sheetName string) string {
+	return "'" + strings.ReplaceAll(sheetName, "'", "''") + "'"
+}
+
+// a1Range builds an A1 notation range for the specified sheet
+func a1Range(sheetName, cells string) string {
+	return quoteSheetName(sheetName) + "!" + cells
+}
+
+// rowsEqual reports whether two rows have the same cells, ignoring trailing empty cells
+func rowsEqual(a, b []string) bool {
+	a = trimTrailingEmpty(a)
+	b = trimTrailingEmpty(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trimTrailingEmpty removes trailing empty cells from a row
+func trimTrailingEmpty(row []string) []string {
+	for len(row) > 0 && row[len(row)-1] == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+// findSheet returns the properties of the sheet with the given name, or nil if not found.
+// Sheet names are compared case-insensitively, as Google Sheets does.
+func findSheet(props []*sheets.SheetProperties, sheetName string) *sheets.SheetProperties {
+	for _, p := range props {
+		if strings.EqualFold(p.Title, sheetName) {
+			return p
+		}
+	}
+	return nil
+}
+
+// MaxSheetNameLength is the maximum number of characters in a sheet name
+const MaxSheetNameLength = 100
+
+// uniqueSheetName returns sheetName suffixed with the smallest counter that does not
+// collide with an existing sheet, e.g. "Report (2)"
+func uniqueSheetName(props []*sheets.SheetProperties, sheetName string) string {
+	for i := 2; ; i++ {
+		candidate := numberedSheetName(sheetName, i)
+		if findSheet(props, candidate) == nil {
+			return candidate
+		}
+	}
+}
+
+// numberedSheetName returns sheetName suffixed with the counter, e.g. "Report (2)",
+// shortening sheetName so that the result fits in MaxSheetNameLength characters
+func numberedSheetName(sheetName string, n int) string {
+	suffix := fmt.Sprintf(" (%d)", n)
+	if runes := []rune(sheetName); len(runes)+len(suffix) > MaxSheetNameLength {
+		sheetName = string(runes[:MaxSheetNameLength-len(suffix)])
+	}
+	return sheetName + suffix
+}
+
+// sheetURL returns the URL of a specific sheet in the spreadsheet
+func sheetURL(spreadsheetID string, sheetID int64) string {
+	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit#gid=%d", spreadsheetID, sheetID)
+}
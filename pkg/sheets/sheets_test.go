@@ -0,0 +1,131 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestAppendToSpreadsheet(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   [][]interface{}
+		data      [][]string
+		wantRange string
+		wantRows  int
+	}{
+		{
+			name:      "after a blank row and a side table",
+			current:   testValues([]string{"id", "name"}, []string{"1", "a", "", "total"}, []string{"", "", "", "3"}, nil, []string{"2", "b"}),
+			data:      [][]string{{"id", "name"}, {"3", "c"}},
+			wantRange: "'Sheet1'!A6",
+			wantRows:  1,
+		},
+		{
+			name:      "empty sheet keeps the header",
+			current:   nil,
+			data:      [][]string{{"id", "name"}, {"1", "a"}},
+			wantRange: "'Sheet1'!A1",
+			wantRows:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSheets{props: testProps(1000, 26), values: tt.current}
+			c := newFakeClient(t, fake)
+
+			if _, err := c.AppendToSpreadsheet(context.Background(), "test", tt.data, SheetOptions{}); err != nil {
+				t.Fatalf("AppendToSpreadsheet() error = %v", err)
+			}
+			writes := fake.find(http.MethodPut, "/values/")
+			if len(writes) != 1 {
+				t.Fatalf("got %d writes, want 1", len(writes))
+			}
+			if got := writes[0].path; got != "/values/"+tt.wantRange {
+				t.Errorf("written at %s, want %s", got, tt.wantRange)
+			}
+			if got := len(writes[0].body["values"].([]interface{})); got != tt.wantRows {
+				t.Errorf("wrote %d rows, want %d", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestAppendToSpreadsheetGrowsGrid(t *testing.T) {
+	fake := &fakeSheets{props: testProps(2, 2), values: testValues([]string{"a", "b"}, []string{"1", "2"})}
+	c := newFakeClient(t, fake)
+
+	data := [][]string{{"a", "b"}, {"3", "4", "5"}, {"6", "7"}}
+	if _, err := c.AppendToSpreadsheet(context.Background(), "test", data, SheetOptions{}); err != nil {
+		t.Fatalf("AppendToSpreadsheet() error = %v", err)
+	}
+	if got, want := fake.batchRequests(), []string{"appendDimension", "appendDimension"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batch requests = %v, want %v", got, want)
+	}
+}
+
+func TestUniqueSheetName(t *testing.T) {
+	long := strings.Repeat("あ", MaxSheetNameLength)
+	existing := []*sheets.SheetProperties{
+		{Title: "Report"},
+		{Title: "Report (2)"},
+		{Title: long},
+		{Title: strings.Repeat("あ", MaxSheetNameLength-4) + " (2)"},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Report", "Report (3)"},
+		{"Other", "Other (2)"},
+		{long, strings.Repeat("あ", MaxSheetNameLength-4) + " (3)"},
+	}
+
+	for _, tt := range tests {
+		got := uniqueSheetName(existing, tt.name)
+		if got != tt.want {
+			t.Errorf("uniqueSheetName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); n > MaxSheetNameLength {
+			t.Errorf("uniqueSheetName(%q) has %d characters, want at most %d", tt.name, n, MaxSheetNameLength)
+		}
+	}
+}
+
+func TestApplySheetOptionsWithoutColumns(t *testing.T) {
+	bold, _ := HeaderStylePreset(HeaderStyleBold)
+	opts := SheetOptions{FreezeRows: 1, FilterHeaderRow: 1, HeaderStyle: bold, AutoResize: true, MaxColWidth: 100}
+
+	tests := []struct {
+		name    string
+		numCols int
+		want    []string
+	}{
+		{"columns", 2, []string{"updateSheetProperties", "setBasicFilter", "repeatCell", "autoResizeDimensions"}},
+		{"no columns", 0, []string{"updateSheetProperties"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSheets{props: testProps(10, 2)}
+			client := newFakeClient(t, fake)
+			if err := client.applySheetOptions(context.Background(), "test", 7, 3, tt.numCols, opts); err != nil {
+				t.Fatalf("applySheetOptions() error = %v", err)
+			}
+			got := fake.batchRequests()
+			if len(got) < len(tt.want) || !reflect.DeepEqual(got[:len(tt.want)], tt.want) {
+				t.Errorf("requests = %v, want %v first", got, tt.want)
+			}
+			if tt.numCols == 0 && len(got) != len(tt.want) {
+				t.Errorf("requests = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
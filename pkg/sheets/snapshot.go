@@ -0,0 +1,132 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Orders of snapshot sheets in the spreadsheet
+const (
+	// SnapshotNewestFirst places each new snapshot before the existing ones
+	SnapshotNewestFirst = "newest-first"
+	// SnapshotNewestLast places each new snapshot after the existing ones
+	SnapshotNewestLast = "newest-last"
+)
+
+// snapshotNamePattern matches the names of sheets created by AddSnapshotSheet,
+// i.e. the default title timestamp optionally followed by a collision counter
+var snapshotNamePattern = regexp.MustCompile(`^\d{14}\+gs( \(\d+\))?$`)
+
+// SnapshotResult summarizes the changes made by AddSnapshotSheet
+type SnapshotResult struct {
+	// URL is the URL of the new snapshot sheet
+	URL string
+	// SheetName is the name of the new snapshot sheet
+	SheetName string
+	// Pruned lists the names of old snapshot sheets that were deleted
+	Pruned []string
+}
+
+// AddSnapshotSheet adds a new sheet named by the current timestamp to an existing
+// spreadsheet, writes data to it and deletes the oldest snapshot sheets so that at most
+// keep snapshots remain (0 keeps all). Sheets whose names do not look like snapshots
+// are never deleted. order is SnapshotNewestFirst or SnapshotNewestLast.
+func (c *Client) AddSnapshotSheet(ctx context.Context, spreadsheetID string, data [][]string, keep int, order string, opts SheetOptions) (*SnapshotResult, error) {
+	existing, err := c.getSheetProperties(ctx, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheetName := generateDefaultTitle()
+	if findSheet(existing, sheetName) != nil {
+		sheetName = uniqueSheetName(existing, sheetName)
+	}
+
+	snapshots := snapshotSheets(existing)
+
+	// Keep the snapshot sheets grouped together in the requested order
+	index := -1
+	switch order {
+	case SnapshotNewestFirst:
+		if len(snapshots) > 0 {
+			index = int(snapshots[len(snapshots)-1].Index)
+		}
+	case SnapshotNewestLast:
+		if len(snapshots) > 0 {
+			index = int(snapshots[len(snapshots)-1].Index) + 1
+		}
+	default:
+		return nil, fmt.Errorf("unknown snapshot order: %s (supported: %s, %s)", order, SnapshotNewestFirst, SnapshotNewestLast)
+	}
+
+	props, err := c.addSheetAt(ctx, spreadsheetID, sheetName, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add sheet: %w", err)
+	}
+
+	if len(data) > 0 {
+		if err := c.writeData(ctx, spreadsheetID, sheetName, data); err != nil {
+			return nil, fmt.Errorf("failed to write data: %w", err)
+		}
+		if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, len(data), len(data[0]), opts); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &SnapshotResult{
+		URL:       sheetURL(spreadsheetID, props.SheetId),
+		SheetName: sheetName,
+	}
+
+	// Delete the oldest snapshots beyond the retention count (the new one counts too)
+	if keep > 0 && len(snapshots)+1 > keep {
+		pruned := snapshots[:len(snapshots)+1-keep]
+		if err := c.deleteSheets(ctx, spreadsheetID, pruned); err != nil {
+			return nil, fmt.Errorf("failed to delete old snapshots: %w", err)
+		}
+		for _, p := range pruned {
+			result.Pruned = append(result.Pruned, p.Title)
+		}
+	}
+
+	return result, nil
+}
+
+// snapshotSheets returns the snapshot sheets sorted from oldest to newest
+func snapshotSheets(props []*sheets.SheetProperties) []*sheets.SheetProperties {
+	var snapshots []*sheets.SheetProperties
+	for _, p := range props {
+		if snapshotNamePattern.MatchString(p.Title) {
+			snapshots = append(snapshots, p)
+		}
+	}
+
+	// The timestamp format sorts chronologically as a string
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Title < snapshots[j].Title
+	})
+	return snapshots
+}
+
+// deleteSheets deletes the given sheets in a single request
+func (c *Client) deleteSheets(ctx context.Context, spreadsheetID string, props []*sheets.SheetProperties) error {
+	var requests []*sheets.Request
+	for _, p := range props {
+		requests = append(requests, &sheets.Request{
+			DeleteSheet: &sheets.DeleteSheetRequest{
+				SheetId: p.SheetId,
+			},
+		})
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	return err
+}
@@ -0,0 +1,196 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Default bounds of the chunk of rows written by a single request
+const (
+	// DefaultChunkRows is the maximum number of rows per request
+	DefaultChunkRows = 10000
+	// DefaultChunkBytes is the approximate maximum size of the cell data per request
+	DefaultChunkBytes = 4 << 20
+)
+
+// cellOverhead approximates the bytes a cell adds to a request besides its text
+// (quotes and separator)
+const cellOverhead = 3
+
+// RowReader reads rows one at a time and returns io.EOF after the last row.
+// *csv.Reader satisfies it.
+type RowReader interface {
+	Read() ([]string, error)
+}
+
+// StreamOptions bounds the chunks of rows written by CreateSpreadsheetFromStream
+type StreamOptions struct {
+	// ChunkRows is the maximum number of rows per request (0 means DefaultChunkRows)
+	ChunkRows int
+	// ChunkBytes is the approximate maximum size of a request (0 means DefaultChunkBytes).
+	// A single row larger than this is still written in its own request.
+	ChunkBytes int
+	// Progress is called after each chunk with the number of rows and bytes written so far
+	Progress func(rows int, bytes int64)
+}
+
+// CreateSpreadsheetFromStream creates a new spreadsheet with a single sheet and writes
+// the rows read from rows in chunks, growing the grid as needed. Only one chunk is held
+// in memory at a time, so memory use does not depend on the size of the input.
+func (c *Client) CreateSpreadsheetFromStream(ctx context.Context, title, sheetName string, rows RowReader, opts SheetOptions, stream StreamOptions) (string, error) {
+	chunks := newChunkReader(rows, stream.ChunkRows, stream.ChunkBytes)
+
+	// Read the first chunk before creating the spreadsheet so that empty input creates nothing
+	chunk, size, err := chunks.next()
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(chunk) == 0 {
+		return "", fmt.Errorf("no data provided")
+	}
+	if c.formulaPolicy == FormulaReject {
+		if err := checkFormulas(chunk, 0); err != nil {
+			return "", err
+		}
+	}
+
+	// If no title is provided, generate one from timestamp
+	if title == "" {
+		title = generateDefaultTitle()
+	}
+	if sheetName == "" {
+		sheetName = DefaultSheetName
+	}
+
+	spreadsheet := &sheets.Spreadsheet{
+		Properties: &sheets.SpreadsheetProperties{
+			Title: title,
+		},
+		Sheets: []*sheets.Sheet{
+			{Properties: &sheets.SheetProperties{Title: sheetName}},
+		},
+	}
+	resp, err := c.service.Spreadsheets.Create(spreadsheet).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create spreadsheet: %w", err)
+	}
+	spreadsheetID := resp.SpreadsheetId
+	props := resp.Sheets[0].Properties
+
+	numRows, numCols := 0, 0
+	var written int64
+	for len(chunk) > 0 {
+		if width := maxRowWidth(chunk); width > numCols {
+			numCols = width
+		}
+		if err := c.ensureGridSize(ctx, spreadsheetID, props, numRows+len(chunk), numCols); err != nil {
+			return "", fmt.Errorf("failed to resize %s: %w", sheetName, err)
+		}
+
+		rangeStr := a1Range(sheetName, fmt.Sprintf("A%d", numRows+1))
+		if err := c.writeRange(ctx, spreadsheetID, rangeStr, chunk); err != nil {
+			return "", fmt.Errorf("failed to write rows %d-%d to %s: %w", numRows+1, numRows+len(chunk), sheetName, err)
+		}
+		numRows += len(chunk)
+		written += size
+		if stream.Progress != nil {
+			stream.Progress(numRows, written)
+		}
+
+		chunk, size, err = chunks.next()
+		if err != nil {
+			return "", fmt.Errorf("failed to read input after row %d: %w", numRows, err)
+		}
+		if c.formulaPolicy == FormulaReject {
+			if err := checkFormulas(chunk, numRows); err != nil {
+				return "", fmt.Errorf("%w (%d rows were already uploaded)", err, numRows)
+			}
+		}
+	}
+
+	// Apply freeze panes, basic filter and header style
+	if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, numRows, numCols, opts); err != nil {
+		return "", err
+	}
+
+	return sheetURL(spreadsheetID, props.SheetId), nil
+}
+
+// chunkReader groups the rows of a RowReader into chunks bounded by the number of
+// rows and the approximate request size
+type chunkReader struct {
+	rows     RowReader
+	maxRows  int
+	maxBytes int64
+	// pending is a row read past the end of the previous chunk
+	pending    []string
+	hasPending bool
+}
+
+// newChunkReader returns a chunkReader with the given bounds (0 means the default)
+func newChunkReader(rows RowReader, maxRows, maxBytes int) *chunkReader {
+	if maxRows <= 0 {
+		maxRows = DefaultChunkRows
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultChunkBytes
+	}
+	return &chunkReader{rows: rows, maxRows: maxRows, maxBytes: int64(maxBytes)}
+}
+
+// next returns the next chunk and its approximate size (an empty chunk at the end)
+func (r *chunkReader) next() ([][]string, int64, error) {
+	var chunk [][]string
+	var size int64
+	for len(chunk) < r.maxRows {
+		row := r.pending
+		if r.hasPending {
+			r.hasPending = false
+		} else {
+			var err error
+			row, err = r.rows.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		rowSize := rowBytes(row)
+		if len(chunk) > 0 && size+rowSize > r.maxBytes {
+			r.pending, r.hasPending = row, true
+			break
+		}
+		chunk = append(chunk, row)
+		size += rowSize
+	}
+	return chunk, size, nil
+}
+
+// sliceRows is a RowReader over rows already in memory
+type sliceRows struct {
+	data [][]string
+}
+
+// Read returns the next row
+func (s *sliceRows) Read() ([]string, error) {
+	if len(s.data) == 0 {
+		return nil, io.EOF
+	}
+	row := s.data[0]
+	s.data = s.data[1:]
+	return row, nil
+}
+
+// rowBytes approximates the size of a row in a request
+func rowBytes(row []string) int64 {
+	size := int64(0)
+	for _, cell := range row {
+		size += int64(len(cell) + cellOverhead)
+	}
+	return size
+}
@@ -0,0 +1,110 @@
+package sheets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingRows is a RowReader returning its rows and then err
+type failingRows struct {
+	sliceRows
+	err error
+}
+
+// Read returns the next row, or err after the last row
+func (f *failingRows) Read() ([]string, error) {
+	if len(f.data) == 0 {
+		return nil, f.err
+	}
+	return f.sliceRows.Read()
+}
+
+func TestChunkReaderNext(t *testing.T) {
+	rows := func(n int) [][]string {
+		data := make([][]string, n)
+		for i := range data {
+			data[i] = []string{string(rune('a' + i))}
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		data     [][]string
+		maxRows  int
+		maxBytes int
+		// want is the number of rows of each chunk before the empty one
+		want []int
+	}{
+		{"exact multiple of the chunk size", rows(4), 2, 0, []int{2, 2}},
+		{"short last chunk", rows(5), 2, 0, []int{2, 2, 1}},
+		{"single chunk", rows(3), 10, 0, []int{3}},
+		{"empty input", nil, 2, 0, nil},
+		// Each row is 1+cellOverhead = 4 bytes
+		{"bounded by bytes", rows(5), 10, 8, []int{2, 2, 1}},
+		{"row larger than the byte bound", [][]string{{"a"}, {"0123456789"}, {"b"}}, 10, 5, []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := newChunkReader(&sliceRows{data: tt.data}, tt.maxRows, tt.maxBytes)
+			var got []int
+			var all [][]string
+			for {
+				chunk, size, err := chunks.next()
+				if err != nil {
+					t.Fatalf("next() error = %v", err)
+				}
+				if len(chunk) == 0 {
+					break
+				}
+				if want := totalBytes(chunk); size != want {
+					t.Errorf("next() size = %d, want %d", size, want)
+				}
+				got = append(got, len(chunk))
+				all = append(all, chunk...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunk rows = %v, want %v", got, tt.want)
+			}
+			if len(tt.data) > 0 && !reflect.DeepEqual(all, tt.data) {
+				t.Errorf("rows = %v, want %v", all, tt.data)
+			}
+			// The reader keeps returning empty chunks at the end
+			if chunk, _, err := chunks.next(); len(chunk) != 0 || err != nil {
+				t.Errorf("next() after the end = %v, %v, want an empty chunk", chunk, err)
+			}
+		})
+	}
+}
+
+func TestChunkReaderNextError(t *testing.T) {
+	readErr := errors.New("read failed")
+	chunks := newChunkReader(&failingRows{sliceRows: sliceRows{data: [][]string{{"a"}, {"b"}, {"c"}}}, err: readErr}, 2, 0)
+
+	chunk, _, err := chunks.next()
+	if err != nil {
+		t.Fatalf("next() error = %v", err)
+	}
+	if want := [][]string{{"a"}, {"b"}}; !reflect.DeepEqual(chunk, want) {
+		t.Errorf("next() = %v, want %v", chunk, want)
+	}
+
+	chunk, _, err = chunks.next()
+	if !errors.Is(err, readErr) {
+		t.Errorf("next() error = %v, want %v", err, readErr)
+	}
+	if chunk != nil {
+		t.Errorf("next() = %v, want no rows with the error", chunk)
+	}
+}
+
+// totalBytes returns the approximate request size of rows
+func totalBytes(rows [][]string) int64 {
+	var size int64
+	for _, row := range rows {
+		size += rowBytes(row)
+	}
+	return size
+}
@@ -0,0 +1,151 @@
+package sheets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Presets of header styles
+const (
+	// HeaderStyleNone leaves the header row as it is
+	HeaderStyleNone = "none"
+	// HeaderStyleBold makes the header text bold
+	HeaderStyleBold = "bold"
+	// HeaderStyleBoldBackground makes the header text bold and centered on a light gray
+	// background with a bottom border
+	HeaderStyleBoldBackground = "bold+background"
+	// HeaderStyleDark writes the header in bold white text, centered on a dark gray
+	// background with a bottom border
+	HeaderStyleDark = "dark"
+)
+
+// Borders of the header cells
+const (
+	// BorderNone draws no borders
+	BorderNone = "none"
+	// BorderBottom draws a line under the header row
+	BorderBottom = "bottom"
+	// BorderAll draws lines around every header cell
+	BorderAll = "all"
+)
+
+// Horizontal alignments of the header cells
+const (
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+)
+
+// HeaderStyle is the format applied to the header row
+type HeaderStyle struct {
+	// Bold makes the text bold
+	Bold bool
+	// Background is the background color as "#RRGGBB" ("" leaves it as it is)
+	Background string
+	// Foreground is the text color as "#RRGGBB" ("" leaves it as it is)
+	Foreground string
+	// Border is which borders are drawn (BorderNone, BorderBottom or BorderAll; "" is BorderNone)
+	Border string
+	// Align is the horizontal alignment (AlignLeft, AlignCenter or AlignRight; "" leaves it as it is)
+	Align string
+}
+
+// colorPattern matches colors written as "#RRGGBB"
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// HeaderStylePreset returns the style of a preset, or nil for HeaderStyleNone
+func HeaderStylePreset(name string) (*HeaderStyle, error) {
+	switch name {
+	case HeaderStyleNone:
+		return nil, nil
+	case HeaderStyleBold:
+		return &HeaderStyle{Bold: true}, nil
+	case HeaderStyleBoldBackground:
+		return &HeaderStyle{Bold: true, Background: "#E8EAED", Border: BorderBottom, Align: AlignCenter}, nil
+	case HeaderStyleDark:
+		return &HeaderStyle{Bold: true, Background: "#434343", Foreground: "#FFFFFF", Border: BorderBottom, Align: AlignCenter}, nil
+	default:
+		return nil, fmt.Errorf("unknown header style: %s (supported: %s, %s, %s, %s)", name, HeaderStyleNone, HeaderStyleBold, HeaderStyleBoldBackground, HeaderStyleDark)
+	}
+}
+
+// Validate checks the colors, border and alignment of the style
+func (s HeaderStyle) Validate() error {
+	for _, color := range []string{s.Background, s.Foreground} {
+		if color != "" && !colorPattern.MatchString(color) {
+			return fmt.Errorf("invalid color: %s (must be #RRGGBB)", color)
+		}
+	}
+	switch s.Border {
+	case "", BorderNone, BorderBottom, BorderAll:
+	default:
+		return fmt.Errorf("invalid border: %s (supported: %s, %s, %s)", s.Border, BorderNone, BorderBottom, BorderAll)
+	}
+	switch s.Align {
+	case "", AlignLeft, AlignCenter, AlignRight:
+	default:
+		return fmt.Errorf("invalid alignment: %s (supported: %s, %s, %s)", s.Align, AlignLeft, AlignCenter, AlignRight)
+	}
+	return nil
+}
+
+// headerStyleRequest returns the request that formats the 1-indexed header row.
+// Only the properties set in the style are changed.
+func headerStyleRequest(sheetID int64, headerRow, numCols int, style HeaderStyle) *sheets.Request {
+	format := &sheets.CellFormat{
+		TextFormat: &sheets.TextFormat{Bold: style.Bold, ForceSendFields: []string{"Bold"}},
+	}
+	fields := []string{"textFormat.bold"}
+	if style.Foreground != "" {
+		format.TextFormat.ForegroundColor = parseColor(style.Foreground)
+		fields = append(fields, "textFormat.foregroundColor")
+	}
+	if style.Background != "" {
+		format.BackgroundColor = parseColor(style.Background)
+		fields = append(fields, "backgroundColor")
+	}
+	if style.Border == BorderBottom || style.Border == BorderAll {
+		line := &sheets.Border{Style: "SOLID"}
+		format.Borders = &sheets.Borders{Bottom: line}
+		if style.Border == BorderAll {
+			format.Borders.Top, format.Borders.Left, format.Borders.Right = line, line, line
+		}
+		fields = append(fields, "borders")
+	}
+	if style.Align != "" {
+		format.HorizontalAlignment = strings.ToUpper(style.Align)
+		fields = append(fields, "horizontalAlignment")
+	}
+
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    int64(headerRow - 1), // Convert to 0-indexed
+				EndRowIndex:      int64(headerRow),
+				StartColumnIndex: 0,
+				EndColumnIndex:   int64(numCols),
+			},
+			Cell:   &sheets.CellData{UserEnteredFormat: format},
+			Fields: "userEnteredFormat(" + strings.Join(fields, ",") + ")",
+		},
+	}
+}
+
+// parseColor converts a color written as "#RRGGBB" to an API color
+func parseColor(color string) *sheets.Color {
+	component := func(i int) float64 {
+		n, _ := strconv.ParseUint(color[i:i+2], 16, 8)
+		return float64(n) / 255
+	}
+	return &sheets.Color{
+		Red:             component(1),
+		Green:           component(3),
+		Blue:            component(5),
+		ForceSendFields: []string{"Red", "Green", "Blue"},
+	}
+}
@@ -0,0 +1,134 @@
+package sheets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeaderStylePreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    *HeaderStyle
+		wantErr bool
+	}{
+		{HeaderStyleNone, nil, false},
+		{HeaderStyleBold, &HeaderStyle{Bold: true}, false},
+		{HeaderStyleBoldBackground, &HeaderStyle{Bold: true, Background: "#E8EAED", Border: BorderBottom, Align: AlignCenter}, false},
+		{HeaderStyleDark, &HeaderStyle{Bold: true, Background: "#434343", Foreground: "#FFFFFF", Border: BorderBottom, Align: AlignCenter}, false},
+		{"Bold", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HeaderStylePreset(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HeaderStylePreset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HeaderStylePreset() = %+v, want %+v", got, tt.want)
+			}
+			if got != nil {
+				if err := got.Validate(); err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestHeaderStyleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   HeaderStyle
+		wantErr bool
+	}{
+		{"empty", HeaderStyle{}, false},
+		{"colors", HeaderStyle{Background: "#a0b1C2", Foreground: "#000000"}, false},
+		{"border and alignment", HeaderStyle{Border: BorderAll, Align: AlignRight}, false},
+		{"color without #", HeaderStyle{Background: "FFFFFF"}, true},
+		{"short color", HeaderStyle{Foreground: "#FFF"}, true},
+		{"color name", HeaderStyle{Background: "red"}, true},
+		{"color with alpha", HeaderStyle{Background: "#FFFFFF00"}, true},
+		{"not hexadecimal", HeaderStyle{Foreground: "#GGGGGG"}, true},
+		{"unknown border", HeaderStyle{Border: "top"}, true},
+		{"unknown alignment", HeaderStyle{Align: "justify"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.style.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeaderStyleRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		style      HeaderStyle
+		wantFields string
+		wantBorder []bool // top, bottom, left, right
+	}{
+		{
+			name:       "bold only",
+			style:      HeaderStyle{Bold: true},
+			wantFields: "userEnteredFormat(textFormat.bold)",
+		},
+		{
+			name:       "not bold still resets bold",
+			style:      HeaderStyle{Align: AlignLeft},
+			wantFields: "userEnteredFormat(textFormat.bold,horizontalAlignment)",
+		},
+		{
+			name:       "dark",
+			style:      HeaderStyle{Bold: true, Background: "#434343", Foreground: "#FFFFFF", Border: BorderBottom, Align: AlignCenter},
+			wantFields: "userEnteredFormat(textFormat.bold,textFormat.foregroundColor,backgroundColor,borders,horizontalAlignment)",
+			wantBorder: []bool{false, true, false, false},
+		},
+		{
+			name:       "all borders",
+			style:      HeaderStyle{Border: BorderAll},
+			wantFields: "userEnteredFormat(textFormat.bold,borders)",
+			wantBorder: []bool{true, true, true, true},
+		},
+		{
+			name:       "no borders",
+			style:      HeaderStyle{Border: BorderNone},
+			wantFields: "userEnteredFormat(textFormat.bold)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := headerStyleRequest(7, 2, 3, tt.style).RepeatCell
+			if req.Fields != tt.wantFields {
+				t.Errorf("Fields = %q, want %q", req.Fields, tt.wantFields)
+			}
+			r := req.Range
+			if r.SheetId != 7 || r.StartRowIndex != 1 || r.EndRowIndex != 2 || r.StartColumnIndex != 0 || r.EndColumnIndex != 3 {
+				t.Errorf("Range = %+v, want row 2 of columns A-C", r)
+			}
+
+			format := req.Cell.UserEnteredFormat
+			if format.TextFormat.Bold != tt.style.Bold {
+				t.Errorf("Bold = %v, want %v", format.TextFormat.Bold, tt.style.Bold)
+			}
+			var border []bool
+			if b := format.Borders; b != nil {
+				border = []bool{b.Top != nil, b.Bottom != nil, b.Left != nil, b.Right != nil}
+			}
+			if !reflect.DeepEqual(border, tt.wantBorder) {
+				t.Errorf("Borders = %v, want %v", border, tt.wantBorder)
+			}
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	color := parseColor("#FF8000")
+	if color.Red != 1 || color.Green != float64(0x80)/255 || color.Blue != 0 {
+		t.Errorf("parseColor() = %+v, want red 1, green 0.5, blue 0", color)
+	}
+}
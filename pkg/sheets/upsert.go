@@ -0,0 +1,289 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// UpsertResult summarizes the changes made by UpsertToSpreadsheet
+type UpsertResult struct {
+	// URL is the URL of the target sheet
+	URL string
+	// Updated is the number of existing rows that were rewritten
+	Updated int
+	// Appended is the number of new rows added to the end of the sheet
+	Appended int
+	// Unchanged is the number of matched rows that were left as-is
+	Unchanged int
+}
+
+// upsertPlan is the result of comparing the input data with the current sheet values
+type upsertPlan struct {
+	// header is the merged header row (existing columns followed by new ones)
+	header []string
+	// headerChanged is true when new columns were added to the header
+	headerChanged bool
+	// updates maps a 0-indexed row of the sheet to its new contents
+	updates map[int][]string
+	// appends are new rows to be added after the last row
+	appends [][]string
+	// unchanged is the number of matched rows whose values did not change
+	unchanged int
+}
+
+// UpsertToSpreadsheet matches the rows of data against the target sheet by the key column,
+// updates changed rows in place and appends rows whose key is not found.
+// The first row of data and of the sheet is treated as the header, and columns are matched
+// by header name. If sheetName is empty, the first sheet is used. If the named sheet does
+// not exist yet, it is created and all data is written to it.
+func (c *Client) UpsertToSpreadsheet(ctx context.Context, spreadsheetID, sheetName, keyColumn string, data [][]string, opts SheetOptions) (*UpsertResult, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data provided")
+	}
+
+	existing, err := c.getSheetProperties(ctx, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := c.targetSheet(ctx, spreadsheetID, existing, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.readValues(ctx, spreadsheetID, props.Title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet values: %w", err)
+	}
+
+	// Empty sheet: write everything as-is
+	if len(current) == 0 {
+		if _, err := c.writeRowsAfter(ctx, spreadsheetID, props, 0, data); err != nil {
+			return nil, fmt.Errorf("failed to write data: %w", err)
+		}
+		if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, len(data), len(data[0]), opts); err != nil {
+			return nil, err
+		}
+		return &UpsertResult{URL: sheetURL(spreadsheetID, props.SheetId), Appended: len(data) - 1}, nil
+	}
+
+	plan, err := planUpsert(current, data, keyColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the grid is large enough for added columns and rows
+	if err := c.ensureGridSize(ctx, spreadsheetID, props, len(current)+len(plan.appends), len(plan.header)); err != nil {
+		return nil, fmt.Errorf("failed to resize sheet: %w", err)
+	}
+
+	// Rewrite changed rows (and the header if columns were added)
+	updates := make(map[int][]string, len(plan.updates)+1)
+	for row, cells := range plan.updates {
+		updates[row] = cells
+	}
+	if plan.headerChanged {
+		updates[0] = plan.header
+	}
+	if err := c.updateRows(ctx, spreadsheetID, props.Title, updates); err != nil {
+		return nil, fmt.Errorf("failed to update rows: %w", err)
+	}
+
+	// Append new rows after the last non-empty row
+	numRows := len(current)
+	if len(plan.appends) > 0 {
+		numRows, err = c.writeRowsAfter(ctx, spreadsheetID, props, len(current), plan.appends)
+		if err != nil {
+			return nil, fmt.Errorf("failed to append data: %w", err)
+		}
+	}
+
+	if err := c.applySheetOptions(ctx, spreadsheetID, props.SheetId, numRows, len(plan.header), opts); err != nil {
+		return nil, err
+	}
+
+	return &UpsertResult{
+		URL:       sheetURL(spreadsheetID, props.SheetId),
+		Updated:   len(plan.updates),
+		Appended:  len(plan.appends),
+		Unchanged: plan.unchanged,
+	}, nil
+}
+
+// planUpsert compares the input data with the current sheet values and decides
+// which rows have to be updated and which have to be appended
+func planUpsert(current, data [][]string, keyColumn string) (*upsertPlan, error) {
+	header := append([]string(nil), current[0]...)
+	inputHeader := data[0]
+
+	sheetKey := columnIndex(header, keyColumn)
+	if sheetKey < 0 {
+		return nil, fmt.Errorf("key column %q not found in the sheet header", keyColumn)
+	}
+	inputKey := columnIndex(inputHeader, keyColumn)
+	if inputKey < 0 {
+		return nil, fmt.Errorf("key column %q not found in the input header", keyColumn)
+	}
+
+	// Map each input column to a sheet column, adding missing columns to the header
+	plan := &upsertPlan{updates: make(map[int][]string)}
+	mapping := make([]int, len(inputHeader))
+	for i, name := range inputHeader {
+		idx := columnIndex(header, name)
+		if idx < 0 {
+			header = append(header, name)
+			idx = len(header) - 1
+			plan.headerChanged = true
+		}
+		mapping[i] = idx
+	}
+	plan.header = header
+
+	// Index existing rows by key (the first occurrence wins)
+	rowsByKey := make(map[string]int)
+	for i := 1; i < len(current); i++ {
+		key := cellAt(current[i], sheetKey)
+		if key == "" {
+			continue
+		}
+		if _, ok := rowsByKey[key]; !ok {
+			rowsByKey[key] = i
+		}
+	}
+
+	appendedByKey := make(map[string]int)
+	for _, record := range data[1:] {
+		key := cellAt(record, inputKey)
+
+		// Rows without a key or with an unknown key are appended
+		if idx, ok := appendedByKey[key]; ok && key != "" {
+			mergeRow(plan.appends[idx], record, mapping)
+			continue
+		}
+		rowIdx, ok := rowsByKey[key]
+		if !ok || key == "" {
+			row := mergeRow(make([]string, len(header)), record, mapping)
+			plan.appends = append(plan.appends, row)
+			if key != "" {
+				appendedByKey[key] = len(plan.appends) - 1
+			}
+			continue
+		}
+
+		// Merge into the pending update if the key appears more than once in the input
+		base := current[rowIdx]
+		if pending, ok := plan.updates[rowIdx]; ok {
+			base = pending
+		}
+		merged := mergeRow(padRow(base, len(header)), record, mapping)
+		if rowsEqual(merged, current[rowIdx]) {
+			if _, ok := plan.updates[rowIdx]; !ok {
+				plan.unchanged++
+			}
+			continue
+		}
+		plan.updates[rowIdx] = merged
+	}
+
+	return plan, nil
+}
+
+// targetSheet returns the sheet named sheetName, creating it if it does not exist.
+// If sheetName is empty, the first sheet is returned.
+func (c *Client) targetSheet(ctx context.Context, spreadsheetID string, existing []*sheets.SheetProperties, sheetName string) (*sheets.SheetProperties, error) {
+	if sheetName == "" {
+		if len(existing) == 0 {
+			return nil, fmt.Errorf("spreadsheet %s has no sheets", spreadsheetID)
+		}
+		return existing[0], nil
+	}
+
+	if props := findSheet(existing, sheetName); props != nil {
+		return props, nil
+	}
+
+	props, err := c.addSheet(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add sheet: %w", err)
+	}
+	return props, nil
+}
+
+// readValues reads all values of the specified sheet as strings
+func (c *Client) readValues(ctx context.Context, spreadsheetID, sheetName string) ([][]string, error) {
+	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, quoteSheetName(sheetName)).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]string, len(resp.Values))
+	for i, row := range resp.Values {
+		cells := make([]string, len(row))
+		for j, v := range row {
+			cells[j] = fmt.Sprint(v)
+		}
+		values[i] = cells
+	}
+	return values, nil
+}
+
+// updateRows overwrites the given 0-indexed rows of the sheet in a single request
+func (c *Client) updateRows(ctx context.Context, spreadsheetID, sheetName string, rows map[int][]string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var data []*sheets.ValueRange
+	for row, cells := range rows {
+		data = append(data, &sheets.ValueRange{
+			Range:  a1Range(sheetName, fmt.Sprintf("A%d", row+1)),
+			Values: c.toValues([][]string{cells}),
+		})
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateValuesRequest{
+		ValueInputOption: c.valueInputOption,
+		Data:             data,
+	}
+
+	_, err := c.service.Spreadsheets.Values.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	return err
+}
+
+// columnIndex returns the index of the column with the given header name, or -1 if not found
+func columnIndex(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// cellAt returns the cell at index i, or an empty string if the row is shorter
+func cellAt(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// padRow returns a copy of row extended with empty cells to at least width cells
+func padRow(row []string, width int) []string {
+	if width < len(row) {
+		width = len(row)
+	}
+	padded := make([]string, width)
+	copy(padded, row)
+	return padded
+}
+
+// mergeRow writes the cells of record into row at the positions given by mapping
+func mergeRow(row, record []string, mapping []int) []string {
+	for i, idx := range mapping {
+		row[idx] = cellAt(record, i)
+	}
+	return row
+}
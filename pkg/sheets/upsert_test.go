@@ -0,0 +1,154 @@
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPlanUpsert(t *testing.T) {
+	current := [][]string{
+		{"id", "name", "qty"},
+		{"1", "apple", "3"},
+		{"2", "banana", "5"},
+	}
+
+	tests := []struct {
+		name          string
+		data          [][]string
+		key           string
+		wantHeader    []string
+		headerChanged bool
+		wantUpdates   map[int][]string
+		wantAppends   [][]string
+		wantUnchanged int
+		wantErr       bool
+	}{
+		{
+			name:          "matched and new keys",
+			data:          [][]string{{"id", "name", "qty"}, {"1", "apple", "4"}, {"2", "banana", "5"}, {"3", "cherry", "1"}},
+			key:           "id",
+			wantHeader:    []string{"id", "name", "qty"},
+			wantUpdates:   map[int][]string{1: {"1", "apple", "4"}},
+			wantAppends:   [][]string{{"3", "cherry", "1"}},
+			wantUnchanged: 1,
+		},
+		{
+			name:        "columns in another order",
+			data:        [][]string{{"qty", "id"}, {"9", "2"}},
+			key:         "id",
+			wantHeader:  []string{"id", "name", "qty"},
+			wantUpdates: map[int][]string{2: {"2", "banana", "9"}},
+		},
+		{
+			name:        "duplicate keys in the input are merged",
+			data:        [][]string{{"id", "qty"}, {"1", "7"}, {"1", "8"}, {"4", "1"}, {"4", "2"}},
+			key:         "id",
+			wantHeader:  []string{"id", "name", "qty"},
+			wantUpdates: map[int][]string{1: {"1", "apple", "8"}},
+			wantAppends: [][]string{{"4", "", "2"}},
+		},
+		{
+			name:        "rows without a key are appended",
+			data:        [][]string{{"id", "name"}, {"", "x"}, {"", "y"}},
+			key:         "id",
+			wantHeader:  []string{"id", "name", "qty"},
+			wantAppends: [][]string{{"", "x", ""}, {"", "y", ""}},
+		},
+		{
+			name:          "header gains columns",
+			data:          [][]string{{"id", "price", "qty"}, {"2", "100", "5"}, {"5", "50", "1"}},
+			key:           "id",
+			wantHeader:    []string{"id", "name", "qty", "price"},
+			headerChanged: true,
+			wantUpdates:   map[int][]string{2: {"2", "banana", "5", "100"}},
+			wantAppends:   [][]string{{"5", "", "1", "50"}},
+		},
+		{
+			name:    "key column missing from the input",
+			data:    [][]string{{"name"}, {"x"}},
+			key:     "id",
+			wantErr: true,
+		},
+		{
+			name:    "key column missing from the sheet",
+			data:    [][]string{{"code"}, {"x"}},
+			key:     "code",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planUpsert(current, tt.data, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("planUpsert() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planUpsert() error = %v", err)
+			}
+			if !reflect.DeepEqual(plan.header, tt.wantHeader) || plan.headerChanged != tt.headerChanged {
+				t.Errorf("header = %q (changed %v), want %q (changed %v)", plan.header, plan.headerChanged, tt.wantHeader, tt.headerChanged)
+			}
+			if tt.wantUpdates == nil {
+				tt.wantUpdates = map[int][]string{}
+			}
+			if !reflect.DeepEqual(plan.updates, tt.wantUpdates) {
+				t.Errorf("updates = %q, want %q", plan.updates, tt.wantUpdates)
+			}
+			if !reflect.DeepEqual(plan.appends, tt.wantAppends) {
+				t.Errorf("appends = %q, want %q", plan.appends, tt.wantAppends)
+			}
+			if plan.unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %d, want %d", plan.unchanged, tt.wantUnchanged)
+			}
+		})
+	}
+
+	// The current values are not modified
+	if want := [][]string{{"id", "name", "qty"}, {"1", "apple", "3"}, {"2", "banana", "5"}}; !reflect.DeepEqual(current, want) {
+		t.Errorf("current = %q, want %q", current, want)
+	}
+}
+
+func TestUpsertToSpreadsheetGrowsGrid(t *testing.T) {
+	fake := &fakeSheets{
+		props:  testProps(3, 2),
+		values: testValues([]string{"id", "name"}, []string{"1", "a"}, []string{"2", "b"}),
+	}
+	c := newFakeClient(t, fake)
+
+	data := [][]string{{"id", "name", "qty"}, {"1", "a", "3"}, {"3", "c", "1"}}
+	result, err := c.UpsertToSpreadsheet(context.Background(), "test", "", "id", data, SheetOptions{})
+	if err != nil {
+		t.Fatalf("UpsertToSpreadsheet() error = %v", err)
+	}
+	if result.Updated != 1 || result.Appended != 1 {
+		t.Errorf("result = %+v, want 1 updated and 1 appended", result)
+	}
+
+	// The grid grows before any row is written
+	firstWrite := -1
+	for i, call := range fake.calls {
+		if call.method != http.MethodGet {
+			firstWrite = i
+			break
+		}
+	}
+	if firstWrite < 0 {
+		t.Fatal("nothing was written")
+	}
+	if got := fake.calls[firstWrite].path; got != ":batchUpdate" {
+		t.Errorf("first write to %q, want the grid to be resized first", got)
+	}
+	if got, want := fake.batchRequests(), []string{"appendDimension", "appendDimension"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batch requests = %v, want %v", got, want)
+	}
+	if writes := fake.find(http.MethodPut, "/values/'Sheet1'!A4"); len(writes) != 1 {
+		t.Errorf("new rows written %d times at A4, want once", len(writes))
+	}
+}
@@ -0,0 +1,245 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// ColumnFormat is a number format applied to the cells of a column below the header row
+type ColumnFormat struct {
+	// Column is the 0-indexed column
+	Column int
+	// Type is the number format type, e.g. "NUMBER", "CURRENCY", "PERCENT" or "DATE"
+	Type string
+	// Pattern is the number format pattern, e.g. "#,##0.00"
+	Pattern string
+}
+
+// Parts of a number format pattern that are not date, time or number codes
+var (
+	// literalPattern matches quoted strings and escaped characters
+	literalPattern = regexp.MustCompile(`"[^"]*"|\\.`)
+	// sectionPattern matches colors, conditions and locales such as "[Red]" or "[$-409]",
+	// but not elapsed times such as "[h]"
+	sectionPattern = regexp.MustCompile(`\[[^\]hms][^\]]*\]`)
+)
+
+// NumberFormatType returns the number format type of a pattern: "DATE", "DATE_TIME" or
+// "TIME" if it has date or time parts, "PERCENT" if it has "%", "CURRENCY" if it has a
+// currency symbol, "TEXT" for "@" and "NUMBER" otherwise
+func NumberFormatType(pattern string) string {
+	if pattern == "@" {
+		return "TEXT"
+	}
+	codes := strings.ToLower(literalPattern.ReplaceAllString(pattern, ""))
+	plain := sectionPattern.ReplaceAllString(codes, "")
+	hasDate := strings.ContainsAny(plain, "yd")
+	hasTime := strings.ContainsAny(plain, "hs")
+	switch {
+	case hasDate && hasTime:
+		return "DATE_TIME"
+	case hasDate:
+		return "DATE"
+	case hasTime:
+		return "TIME"
+	case strings.Contains(plain, "%"):
+		return "PERCENT"
+	case strings.ContainsAny(codes, "$¥€£₩"):
+		return "CURRENCY"
+	default:
+		return "NUMBER"
+	}
+}
+
+// Number format patterns applied to date and time cells
+const (
+	datePattern        = "yyyy-mm-dd"
+	dateTimePattern    = "yyyy-mm-dd hh:mm:ss"
+	timePattern        = "hh:mm:ss"
+	elapsedTimePattern = "[h]:mm:ss"
+)
+
+// sheetsEpoch is day 0 of spreadsheet serial date numbers
+var sheetsEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// writeSheet writes the data of sheet to the specified sheet, using the typed
+// values if the sheet has them, and then applies the number formats of its dates
+// and columns in a single request
+func (c *Client) writeSheet(ctx context.Context, spreadsheetID, sheetName string, sheetID int64, sheet Sheet) error {
+	var requests []*sheets.Request
+	if sheet.Values == nil {
+		if err := c.writeData(ctx, spreadsheetID, sheetName, sheet.Data); err != nil {
+			return err
+		}
+	} else {
+		if err := c.writeValues(ctx, spreadsheetID, sheetName, sheet.Values); err != nil {
+			return err
+		}
+		requests = dateFormatRequests(sheetID, sheet.Values)
+	}
+
+	// Column formats come after the date formats so that they take precedence
+	for _, format := range sheet.Formats {
+		if len(sheet.Data) < 2 {
+			break
+		}
+		requests = append(requests, numberFormatRequest(sheetID, 1, len(sheet.Data), format.Column, format.Type, format.Pattern))
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	batchUpdateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to set number formats: %w", err)
+	}
+	return nil
+}
+
+// writeValues writes typed values to the specified sheet in chunks of DefaultChunkRows
+// rows. Dates are written as serial numbers, which become real dates in the sheet once
+// they are given a date number format.
+func (c *Client) writeValues(ctx context.Context, spreadsheetID, sheetName string, values [][]interface{}) error {
+	for start := 0; start < len(values); start += DefaultChunkRows {
+		end := min(start+DefaultChunkRows, len(values))
+		valueRange := &sheets.ValueRange{
+			Values: c.toTypedValues(values[start:end]),
+		}
+
+		_, err := c.service.Spreadsheets.Values.Update(
+			spreadsheetID,
+			a1Range(sheetName, fmt.Sprintf("A%d", start+1)),
+			valueRange,
+		).ValueInputOption(c.valueInputOption).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toTypedValues converts typed values for the API: dates become serial numbers, empty
+// cells become empty strings and strings are escaped according to the formula policy
+func (c *Client) toTypedValues(values [][]interface{}) [][]interface{} {
+	result := make([][]interface{}, len(values))
+	for i, row := range values {
+		converted := make([]interface{}, len(row))
+		for j, v := range row {
+			switch v := v.(type) {
+			case nil:
+				converted[j] = ""
+			case time.Time:
+				converted[j] = serialNumber(v)
+			case time.Duration:
+				converted[j] = v.Hours() / 24
+			case string:
+				converted[j] = c.cellValue(v)
+			default:
+				converted[j] = v
+			}
+		}
+		result[i] = converted
+	}
+	return result
+}
+
+// dateFormatRequests returns requests that apply a date, date-time or time number format
+// to every vertical run of date and time cells with the same pattern
+func dateFormatRequests(sheetID int64, values [][]interface{}) []*sheets.Request {
+	numCols := 0
+	for _, row := range values {
+		if len(row) > numCols {
+			numCols = len(row)
+		}
+	}
+
+	var requests []*sheets.Request
+	for col := 0; col < numCols; col++ {
+		start, pattern := -1, ""
+		for row := 0; row <= len(values); row++ {
+			current := ""
+			if row < len(values) && col < len(values[row]) {
+				switch v := values[row][col].(type) {
+				case time.Time:
+					current = dateFormatPattern(v)
+				case time.Duration:
+					current = timeFormatPattern(v)
+				}
+			}
+			if current == pattern {
+				continue
+			}
+			if pattern != "" {
+				formatType := "DATE"
+				switch pattern {
+				case dateTimePattern:
+					formatType = "DATE_TIME"
+				case timePattern, elapsedTimePattern:
+					formatType = "TIME"
+				}
+				requests = append(requests, numberFormatRequest(sheetID, start, row, col, formatType, pattern))
+			}
+			start, pattern = row, current
+		}
+	}
+	return requests
+}
+
+// numberFormatRequest returns a request that sets the number format of the cells in
+// rows [startRow, endRow) of the 0-indexed column
+func numberFormatRequest(sheetID int64, startRow, endRow, col int, formatType, pattern string) *sheets.Request {
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    int64(startRow),
+				EndRowIndex:      int64(endRow),
+				StartColumnIndex: int64(col),
+				EndColumnIndex:   int64(col + 1),
+			},
+			Cell: &sheets.CellData{
+				UserEnteredFormat: &sheets.CellFormat{
+					NumberFormat: &sheets.NumberFormat{
+						Type:    formatType,
+						Pattern: pattern,
+					},
+				},
+			},
+			Fields: "userEnteredFormat.numberFormat",
+		},
+	}
+}
+
+// dateFormatPattern returns the number format pattern for a date: with the time of
+// day unless it is midnight
+func dateFormatPattern(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return datePattern
+	}
+	return dateTimePattern
+}
+
+// timeFormatPattern returns the number format pattern for a time: elapsed hours if it
+// is a day or longer
+func timeFormatPattern(d time.Duration) string {
+	if d < 0 || d >= 24*time.Hour {
+		return elapsedTimePattern
+	}
+	return timePattern
+}
+
+// serialNumber converts a time into a spreadsheet serial date number, treating
+// its wall clock time as the cell value
+func serialNumber(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(sheetsEpoch).Hours() / 24
+}
@@ -0,0 +1,84 @@
+package sheets
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToTypedValues(t *testing.T) {
+	c := &Client{}
+	values := [][]interface{}{
+		{"name", 1.5, true, nil},
+		{time.Date(1900, time.January, 1, 12, 0, 0, 0, time.UTC), 6 * time.Hour, 36 * time.Hour},
+	}
+	want := [][]interface{}{
+		{"name", 1.5, true, ""},
+		{2.5, 0.25, 1.5},
+	}
+
+	if got := c.toTypedValues(values); !reflect.DeepEqual(got, want) {
+		t.Errorf("toTypedValues() = %v, want %v", got, want)
+	}
+}
+
+func TestDateFormatRequests(t *testing.T) {
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dateTime := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+	values := [][]interface{}{
+		{"date", "time"},
+		{date, 9 * time.Hour},
+		{date, 10 * time.Hour},
+		{dateTime, 30 * time.Hour},
+	}
+
+	type format struct {
+		startRow, endRow, col int64
+		formatType, pattern   string
+	}
+	want := []format{
+		{1, 3, 0, "DATE", datePattern},
+		{3, 4, 0, "DATE_TIME", dateTimePattern},
+		{1, 3, 1, "TIME", timePattern},
+		{3, 4, 1, "TIME", elapsedTimePattern},
+	}
+
+	var got []format
+	for _, r := range dateFormatRequests(0, values) {
+		rng := r.RepeatCell.Range
+		nf := r.RepeatCell.Cell.UserEnteredFormat.NumberFormat
+		got = append(got, format{rng.StartRowIndex, rng.EndRowIndex, rng.StartColumnIndex, nf.Type, nf.Pattern})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dateFormatRequests() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNumberFormatType(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"yyyy-mm-dd", "DATE"},
+		{"yyyy/mm/dd hh:mm", "DATE_TIME"},
+		{"hh:mm:ss", "TIME"},
+		{"[h]:mm", "TIME"},
+		{"0.00%", "PERCENT"},
+		{"$#,##0.00", "CURRENCY"},
+		{"[$¥-411]#,##0", "CURRENCY"},
+		{"#,##0;[Red]-#,##0", "NUMBER"},
+		{`0" days"`, "NUMBER"},
+		{`0\d`, "NUMBER"},
+		{"@", "TEXT"},
+		// Patterns without date, time or number codes are numbers
+		{"general", "NUMBER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := NumberFormatType(tt.pattern); got != tt.want {
+				t.Errorf("NumberFormatType(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}